@@ -0,0 +1,103 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// History exports the confirmed transactions of an account to CSV or JSON
+// Lines, one row per transfer leg, e.g. for the month of June:
+//
+//	go run ./examples/history -url http://localhost:3000 -public-key 27F6... \
+//		-from 2018-06-01 -to 2018-07-01 > june.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/proximax-storage/nem2-sdk-go/sdk"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	var (
+		baseURL    = flag.String("url", "http://localhost:3000", "REST url of the node")
+		network    = flag.String("network", "mijin_test", "network type: main_net, test_net, mijin or mijin_test")
+		publicKey  = flag.String("public-key", "", "public key of the account")
+		format     = flag.String("format", "csv", "output format: csv or jsonl")
+		from       = flag.String("from", "", "first day to export, YYYY-MM-DD in UTC")
+		to         = flag.String("to", "", "day to stop the export at, excluded, YYYY-MM-DD in UTC")
+		fromHeight = flag.Uint64("from-height", 0, "first block height to export")
+		toHeight   = flag.Uint64("to-height", 0, "last block height to export")
+		mosaics    = flag.String("mosaics", "", "comma separated full names of the mosaics to export, e.g. nem:xem")
+	)
+	flag.Parse()
+
+	networkType := sdk.NetworkTypeFromString(*network)
+	if networkType == sdk.NotSupportedNet {
+		fail(fmt.Errorf("unknown network %q", *network))
+	}
+
+	account, err := sdk.NewAccountFromPublicKey(*publicKey, networkType)
+	if err != nil {
+		fail(err)
+	}
+
+	opts := &sdk.HistoryOptions{}
+	switch *format {
+	case "csv":
+		opts.Format = sdk.HistoryCSV
+	case "jsonl":
+		opts.Format = sdk.HistoryJSONLines
+	default:
+		fail(fmt.Errorf("unknown format %q", *format))
+	}
+
+	if opts.Filter.From, err = parseDay(*from); err != nil {
+		fail(err)
+	}
+	if opts.Filter.To, err = parseDay(*to); err != nil {
+		fail(err)
+	}
+	if *fromHeight > 0 {
+		opts.Filter.FromHeight = new(big.Int).SetUint64(*fromHeight)
+	}
+	if *toHeight > 0 {
+		opts.Filter.ToHeight = new(big.Int).SetUint64(*toHeight)
+	}
+	for _, name := range strings.Split(*mosaics, ",") {
+		if name == "" {
+			continue
+		}
+		id, err := sdk.NewMosaicIdFromFullName(name)
+		if err != nil {
+			fail(err)
+		}
+		opts.Filter.MosaicIds = append(opts.Filter.MosaicIds, id)
+	}
+
+	conf, err := sdk.NewConfig(*baseURL, networkType)
+	if err != nil {
+		fail(err)
+	}
+	client := sdk.NewClient(nil, conf)
+
+	n, err := client.Account.ExportHistory(context.Background(), account, os.Stdout, opts)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "%d rows exported\n", n)
+}
+
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
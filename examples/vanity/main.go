@@ -0,0 +1,94 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Vanity searches for an account whose address matches a prefix, a suffix
+// or a regular expression, e.g.
+//
+//	go run ./examples/vanity -network mijin_test -prefix SBPRX -timeout 10m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/proximax-storage/nem2-sdk-go/sdk"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"time"
+)
+
+func main() {
+	var (
+		network  = flag.String("network", "mijin_test", "network type: main_net, test_net, mijin or mijin_test")
+		prefix   = flag.String("prefix", "", "address prefix, starting with the network letter")
+		suffix   = flag.String("suffix", "", "address suffix")
+		pattern  = flag.String("regexp", "", "regular expression the address must match")
+		workers  = flag.Int("workers", runtime.NumCPU(), "number of parallel searches")
+		timeout  = flag.Duration("timeout", 0, "give up after this long, 0 for never")
+		interval = flag.Duration("progress", 5*time.Second, "progress report interval")
+	)
+	flag.Parse()
+
+	opts := &sdk.VanityOptions{
+		NetworkType:      sdk.NetworkTypeFromString(*network),
+		Prefix:           *prefix,
+		Suffix:           *suffix,
+		Workers:          *workers,
+		ProgressInterval: *interval,
+		Progress: func(p *sdk.VanityProgress) {
+			if p.Difficulty > 0 {
+				fmt.Fprintf(os.Stderr, "%d addresses in %s (%.0f/s), %.2f%% chance to have found one by now\n",
+					p.Attempts, p.Elapsed.Round(time.Second), p.Rate, p.Probability*100)
+			} else {
+				fmt.Fprintf(os.Stderr, "%d addresses in %s (%.0f/s)\n", p.Attempts, p.Elapsed.Round(time.Second), p.Rate)
+			}
+		},
+	}
+	if opts.NetworkType == sdk.NotSupportedNet {
+		fail(fmt.Errorf("unknown network %q", *network))
+	}
+	if *pattern != "" {
+		re, err := regexp.Compile(*pattern)
+		if err != nil {
+			fail(err)
+		}
+		opts.Regexp = re
+	}
+
+	difficulty, err := sdk.VanityDifficulty(opts)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "expected attempts: %.0f\n", difficulty)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	acc, err := sdk.GenerateVanityAccount(ctx, opts)
+	if err != nil {
+		fail(err)
+	}
+
+	fmt.Println("address:    ", acc.Address.Pretty())
+	fmt.Println("public key: ", acc.KeyPair.PublicKey.String())
+	fmt.Println("private key:", acc.KeyPair.PrivateKey.String())
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
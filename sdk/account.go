@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/proximax-storage/proximax-utils-go/net"
+	"math/big"
 	"net/http"
 )
 
@@ -41,20 +42,220 @@ func (a *AccountService) GetAccountInfo(ctx context.Context, address *Address) (
 	return dto.toStruct()
 }
 
-// GetAccountsInfo return AccountsInfo for different accounts.
+// GetBalance returns the amount of the mosaic owned by the account, zero if it
+// owns none. The divisibility of the mosaic is fetched and cached through
+// MosaicService.GetMosaicDivisibility.
+func (a *AccountService) GetBalance(ctx context.Context, address *Address, mosaicId *MosaicId) (*Amount, error) {
+	if mosaicId == nil {
+		return nil, ErrNilMosaicId
+	}
+
+	info, err := a.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := a.client.Mosaic.GetMosaicDivisibility(ctx, mosaicId)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := big.NewInt(0)
+	for _, m := range info.Mosaics {
+		if mosaicIdToBigInt(m.MosaicId).Cmp(mosaicIdToBigInt(mosaicId)) == 0 {
+			raw = m.Amount
+			break
+		}
+	}
+
+	return NewAmount(mosaicId, raw, d)
+}
+
+// GetAccountLinkInfo returns the role of the account in delegated harvesting
+// and the account it is linked to, if any.
+func (a *AccountService) GetAccountLinkInfo(ctx context.Context, address *Address) (*AccountLinkInfo, error) {
+	if address == nil {
+		return nil, ErrNilAddress
+	}
+
+	if len(address.Address) == 0 {
+		return nil, ErrBlankAddress
+	}
+
+	url := net.NewUrl(fmt.Sprintf(accountRoute, address.Address))
+
+	dto := &accountInfoDTO{}
+
+	resp, err := a.client.DoNewRequest(ctx, http.MethodGet, url.Encode(), nil, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = handleResponseStatusCode(resp, map[int]error{404: ErrResourceNotFound, 409: ErrArgumentNotValid}); err != nil {
+		return nil, err
+	}
+
+	return dto.toLinkInfo(a.client.config.NetworkType)
+}
+
+// ResolvePublicAccount returns the PublicAccount of address, whose public key
+// is known on chain once the account has signed a transaction. It returns a
+// *PublicKeyNotRevealedError when the key is not known yet, including for an
+// account the chain has never seen. Resolved keys are cached by the client.
+func (a *AccountService) ResolvePublicAccount(ctx context.Context, address *Address) (*PublicAccount, error) {
+	if address == nil {
+		return nil, ErrNilAddress
+	}
+
+	if acc, ok := a.client.publicAccounts.get(address); ok {
+		return acc, nil
+	}
+
+	if len(address.Address) == 0 {
+		return nil, ErrBlankAddress
+	}
+
+	url := net.NewUrl(fmt.Sprintf(accountRoute, address.Address))
+
+	dto := &accountInfoDTO{}
+
+	// the node does not know an account which has never been part of a transaction
+	_, err := a.client.doNewRequestOrNotFound(ctx, http.MethodGet, url.Encode(), nil, dto)
+	if err == ErrResourceNotFound {
+		return nil, &PublicKeyNotRevealedError{address}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := dto.toStruct()
+	if err != nil {
+		return nil, err
+	}
+
+	acc := a.client.publicAccounts.add(info)
+	if acc == nil {
+		return nil, &PublicKeyNotRevealedError{address}
+	}
+	return acc, nil
+}
+
+// ResolvePublicAccounts resolves the public accounts of addresses like
+// ResolvePublicAccount. The result is in the order of addresses, with nil
+// for the accounts whose public key is not revealed. The addresses missing
+// from the cache are requested with GetAccountsInfo, so a *BatchError may be
+// returned along with the accounts resolved.
+func (a *AccountService) ResolvePublicAccounts(ctx context.Context, addresses []*Address) ([]*PublicAccount, error) {
+	if len(addresses) == 0 {
+		return nil, ErrEmptyAddressesIds
+	}
+
+	accs := make([]*PublicAccount, len(addresses))
+	missing := make([]*Address, 0)
+
+	for i, address := range addresses {
+		if address == nil {
+			return nil, ErrNilAddress
+		}
+
+		if acc, ok := a.client.publicAccounts.get(address); ok {
+			accs[i] = acc
+		} else {
+			missing = append(missing, address)
+		}
+	}
+
+	if len(missing) == 0 {
+		return accs, nil
+	}
+
+	infos, err := a.GetAccountsInfo(ctx, missing)
+	if batchFailed(err) {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		a.client.publicAccounts.add(info)
+	}
+
+	for i, address := range addresses {
+		if accs[i] == nil {
+			accs[i], _ = a.client.publicAccounts.get(address)
+		}
+	}
+
+	return accs, err
+}
+
+// GetAccountsInfo return AccountsInfo for different accounts, in the order of
+// addresses, followed by the accounts returned for none of addresses. Unknown
+// accounts are left out. Long lists are requested in chunks, see BatchConfig;
+// if only some addresses fail, the other accounts are returned along with a
+// *BatchError.
 func (a *AccountService) GetAccountsInfo(ctx context.Context, addresses []*Address) ([]*AccountInfo, error) {
 	if len(addresses) == 0 {
 		return nil, ErrEmptyAddressesIds
 	}
 
+	ids := make([]string, len(addresses))
+	for i, address := range addresses {
+		if address == nil {
+			return nil, ErrNilAddress
+		}
+		ids[i] = normalizeAddress(address.Address)
+	}
+
+	var (
+		found     = make([]*AccountInfo, len(ids))
+		unmatched = make([][]*AccountInfo, len(ids))
+	)
+
+	err := a.client.doBatch(ctx, ids, func(ctx context.Context, offset int, ids []string) error {
+		accInfos, err := a.getAccountsInfo(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		keys := make([][]string, len(accInfos))
+		for j, accInfo := range accInfos {
+			if accInfo.Address != nil {
+				keys[j] = []string{accInfo.Address.Address}
+			}
+		}
+
+		byId, others := batchMatch(ids, keys)
+		for i, j := range byId {
+			if j >= 0 {
+				found[offset+i] = accInfos[j]
+			}
+		}
+		for _, j := range others {
+			unmatched[offset] = append(unmatched[offset], accInfos[j])
+		}
+		return nil
+	})
+	if batchFailed(err) {
+		return nil, err
+	}
+
+	accInfos := make([]*AccountInfo, 0, len(found))
+	for _, accInfo := range found {
+		if accInfo != nil {
+			accInfos = append(accInfos, accInfo)
+		}
+	}
+	for _, chunk := range unmatched {
+		accInfos = append(accInfos, chunk...)
+	}
+
+	return accInfos, err
+}
+
+func (a *AccountService) getAccountsInfo(ctx context.Context, ids []string) ([]*AccountInfo, error) {
 	addrs := struct {
 		Messages []string `json:"addresses"`
 	}{
-		Messages: make([]string, len(addresses)),
-	}
-
-	for i, address := range addresses {
-		addrs.Messages[i] = address.Address
+		Messages: ids,
 	}
 
 	dtos := accountInfoDTOs(make([]*accountInfoDTO, 0))
@@ -1,9 +1,13 @@
 package sdk
 
 import (
+	"bytes"
+	"container/list"
 	"encoding/base32"
 	"encoding/hex"
 	"github.com/proximax-storage/nem2-crypto-go"
+	"strings"
+	"sync"
 )
 
 var addressNet = map[uint8]NetworkType{
@@ -13,6 +17,54 @@ var addressNet = map[uint8]NetworkType{
 	'S': MijinTest,
 }
 
+const (
+	addressDecodedLength = 25 // network byte, ripemd160 hash and checksum
+	addressRawLength     = 40
+	addressEncodedLength = addressDecodedLength * 2
+	addressPrettyGroup   = 6
+)
+
+// normalizeAddress strips the hyphens and surrounding spaces of a raw or
+// pretty address and upper cases it.
+func normalizeAddress(address string) string {
+	return strings.ToUpper(strings.Replace(strings.TrimSpace(address), "-", "", -1))
+}
+
+// decodeAddress decodes a raw or pretty address and verifies its network
+// byte and checksum.
+func decodeAddress(address string) ([]byte, error) {
+	address = normalizeAddress(address)
+	if address == "" {
+		return nil, ErrBlankAddress
+	}
+
+	if len(address) != addressRawLength {
+		return nil, ErrInvalidAddressLength
+	}
+
+	b, err := base32.StdEncoding.DecodeString(address)
+	if err != nil || len(b) != addressDecodedLength {
+		return nil, ErrInvalidAddressEncoding
+	}
+
+	switch NetworkType(b[0]) {
+	case MainNet, TestNet, Mijin, MijinTest:
+	default:
+		return nil, ErrInvalidAddressNetwork
+	}
+
+	checksum, err := GenerateChecksum(b[:addressDecodedLength-NUM_CHECKSUM_BYTES])
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(checksum, b[addressDecodedLength-NUM_CHECKSUM_BYTES:]) {
+		return nil, ErrInvalidAddressChecksum
+	}
+
+	return b, nil
+}
+
 type accountInfoDTO struct {
 	Account struct {
 		Address          string       `json:"address"`
@@ -22,6 +74,8 @@ type accountInfoDTO struct {
 		Importance       uint64DTO    `json:"importance"`
 		ImportanceHeight uint64DTO    `json:"importanceHeight"`
 		Mosaics          []*mosaicDTO `json:"mosaics"`
+		AccountType      AccountType  `json:"accountType"`
+		LinkedAccountKey string       `json:"linkedAccountKey"`
 	} `json:"account"`
 }
 
@@ -54,6 +108,29 @@ func (dto *accountInfoDTO) toStruct() (*AccountInfo, error) {
 	}, nil
 }
 
+func (dto *accountInfoDTO) toLinkInfo(networkType NetworkType) (*AccountLinkInfo, error) {
+	add, err := NewAddressFromEncoded(dto.Account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &AccountLinkInfo{
+		Address:     add,
+		AccountType: dto.Account.AccountType,
+		Importance:  dto.Account.Importance.toBigInt(),
+	}
+
+	// unlinked accounts report a key of zeros
+	if strings.Trim(dto.Account.LinkedAccountKey, "0") != "" {
+		info.LinkedAccount, err = NewAccountFromPublicKey(dto.Account.LinkedAccountKey, networkType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
 type accountInfoDTOs []*accountInfoDTO
 
 func (a accountInfoDTOs) toStruct() ([]*AccountInfo, error) {
@@ -199,3 +276,79 @@ func generateEncodedAddress(pKey string, version NetworkType) (string, error) {
 	// step 6: base32 encode (5)
 	return base32.StdEncoding.EncodeToString(concatStepThreeAndStepSix), nil
 }
+
+// defaultPublicAccountCacheSize is the maximum number of public accounts a
+// client keeps
+const defaultPublicAccountCacheSize = 10000
+
+// publicAccountCache holds the public accounts resolved by
+// AccountService.ResolvePublicAccount. A public key never changes once
+// revealed, so entries never expire, but the least recently used ones are
+// evicted beyond size, defaultPublicAccountCacheSize when zero.
+type publicAccountCache struct {
+	size int
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+func (c *publicAccountCache) get(address *Address) (*PublicAccount, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[normalizeAddress(address.Address)]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+	return el.Value.(*PublicAccount), true
+}
+
+// add caches the public account of info and returns it, or nil if its public
+// key is not revealed.
+func (c *publicAccountCache) add(info *AccountInfo) *PublicAccount {
+	if info == nil || info.Address == nil || !isPublicKeyRevealed(info) {
+		return nil
+	}
+
+	acc := &PublicAccount{Address: info.Address, PublicKey: info.PublicKey}
+	key := normalizeAddress(info.Address.Address)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.items == nil {
+		c.items = make(map[string]*list.Element)
+		c.lru = list.New()
+	}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = acc
+		c.lru.MoveToFront(el)
+		return acc
+	}
+
+	c.items[key] = c.lru.PushFront(acc)
+
+	size := c.size
+	if size <= 0 {
+		size = defaultPublicAccountCacheSize
+	}
+	for c.lru.Len() > size {
+		back := c.lru.Back()
+		c.lru.Remove(back)
+		delete(c.items, normalizeAddress(back.Value.(*PublicAccount).Address.Address))
+	}
+
+	return acc
+}
+
+func isPublicKeyRevealed(info *AccountInfo) bool {
+	if info.PublicKeyHeight == nil || info.PublicKeyHeight.Sign() == 0 {
+		return false
+	}
+
+	return strings.Trim(info.PublicKey, "0") != ""
+}
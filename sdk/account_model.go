@@ -11,6 +11,7 @@ import (
 	"github.com/proximax-storage/nem2-crypto-go"
 	"github.com/proximax-storage/proximax-utils-go/str"
 	"math/big"
+	"sort"
 	"strings"
 )
 
@@ -66,18 +67,176 @@ func (a *AccountInfo) String() string {
 	)
 }
 
+// ImportanceShare returns the importance of the account as a share of total,
+// e.g. the total importance of the chain, between 0 and 1.
+func (a *AccountInfo) ImportanceShare(total *big.Int) float64 {
+	if a.Importance == nil || total == nil || total.Sign() <= 0 {
+		return 0
+	}
+
+	share, _ := new(big.Rat).SetFrac(a.Importance, total).Float64()
+	return share
+}
+
+// ImportanceRank is the position of an account within a batch of accounts
+// ordered by importance.
+type ImportanceRank struct {
+	Account *AccountInfo
+	// Rank starts at 1, accounts of equal importance share the same rank
+	Rank int
+	// Share is the importance of the account as a share of the batch total
+	Share float64
+}
+
+func (r *ImportanceRank) String() string {
+	return str.StructToString(
+		"ImportanceRank",
+		str.NewField("Account", str.StringPattern, r.Account),
+		str.NewField("Rank", str.IntPattern, r.Rank),
+		str.NewField("Share", "%f", r.Share),
+	)
+}
+
+// RankByImportance orders infos by descending importance, keeping the input
+// order of accounts with the same importance, and ranks them within the batch.
+func RankByImportance(infos []*AccountInfo) []*ImportanceRank {
+	ranks := make([]*ImportanceRank, 0, len(infos))
+	total := big.NewInt(0)
+
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+		ranks = append(ranks, &ImportanceRank{Account: info})
+		if info.Importance != nil {
+			total.Add(total, info.Importance)
+		}
+	}
+
+	importance := func(r *ImportanceRank) *big.Int {
+		if r.Account.Importance == nil {
+			return big.NewInt(0)
+		}
+		return r.Account.Importance
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		return importance(ranks[i]).Cmp(importance(ranks[j])) > 0
+	})
+
+	for i, r := range ranks {
+		r.Rank = i + 1
+		if i > 0 && importance(r).Cmp(importance(ranks[i-1])) == 0 {
+			r.Rank = ranks[i-1].Rank
+		}
+		r.Share = r.Account.ImportanceShare(total)
+	}
+
+	return ranks
+}
+
+// AccountType is the role of an account in delegated harvesting
+type AccountType uint8
+
+// AccountType enums
+const (
+	UnlinkedAccount AccountType = iota
+	// MainAccount has linked its importance to a remote account
+	MainAccount
+	// RemoteAccount harvests with the importance of a main account
+	RemoteAccount
+	// RemoteUnlinkedAccount was a remote account whose main account unlinked it
+	RemoteUnlinkedAccount
+)
+
+func (t AccountType) String() string {
+	return fmt.Sprintf("%d", t)
+}
+
+// AccountLinkInfo describes how an account takes part in delegated
+// harvesting, set up with an AccountLinkTransaction.
+type AccountLinkInfo struct {
+	Address     *Address
+	AccountType AccountType
+	// LinkedAccount is the remote account of a main account or the main
+	// account of a remote one, nil if the account isn't linked
+	LinkedAccount *PublicAccount
+	Importance    *big.Int
+}
+
+func (l *AccountLinkInfo) String() string {
+	return str.StructToString(
+		"AccountLinkInfo",
+		str.NewField("Address", str.StringPattern, l.Address),
+		str.NewField("AccountType", str.StringPattern, l.AccountType),
+		str.NewField("LinkedAccount", str.StringPattern, l.LinkedAccount),
+		str.NewField("Importance", str.StringPattern, l.Importance),
+	)
+}
+
+// IsLinked reports whether the account is either side of an active link.
+func (l *AccountLinkInfo) IsLinked() bool {
+	return (l.AccountType == MainAccount || l.AccountType == RemoteAccount) && l.LinkedAccount != nil
+}
+
+// CanHarvest reports whether the account has importance and harvests with
+// its own key, i.e. it hasn't delegated its importance to a remote account.
+func (l *AccountLinkInfo) CanHarvest() bool {
+	return l.AccountType == UnlinkedAccount && l.Importance != nil && l.Importance.Sign() > 0
+}
+
 type Address struct {
 	Type    NetworkType
 	Address string
 }
 
+// Pretty returns the address in groups of six characters separated by
+// hyphens, e.g. SB3KUB-HATFCP-V7UZQL-WAQ2EU-R6SIHB-SBEOEM-DDJJ.
 func (ad *Address) Pretty() string {
-	res := ""
-	for i := 0; i < 6; i++ {
-		res += ad.Address[i*6:i*6+6] + "-"
+	raw := normalizeAddress(ad.Address)
+
+	parts := make([]string, 0, len(raw)/addressPrettyGroup+1)
+	for len(raw) > addressPrettyGroup {
+		parts = append(parts, raw[:addressPrettyGroup])
+		raw = raw[addressPrettyGroup:]
+	}
+	parts = append(parts, raw)
+
+	return strings.Join(parts, "-")
+}
+
+// Encoded returns the hexadecimal form of the address, as used by the REST API.
+func (ad *Address) Encoded() (string, error) {
+	b, err := decodeAddress(ad.Address)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// Equals reports whether both addresses are the same account,
+// regardless of their formatting.
+func (ad *Address) Equals(other *Address) bool {
+	if ad == nil || other == nil {
+		return ad == other
 	}
-	res += ad.Address[len(ad.Address)-4:]
-	return res
+
+	return normalizeAddress(ad.Address) == normalizeAddress(other.Address)
+}
+
+// IsValidFor reports whether the address is valid and belongs to networkType.
+func (ad *Address) IsValidFor(networkType NetworkType) bool {
+	if ad == nil {
+		return false
+	}
+
+	b, err := decodeAddress(ad.Address)
+	if err != nil {
+		return false
+	}
+
+	return NetworkType(b[0]) == networkType
 }
 
 type MultisigAccountInfo struct {
@@ -151,6 +310,11 @@ func NewAddress(address string, networkType NetworkType) *Address {
 }
 
 func NewAddressFromRaw(address string) (*Address, error) {
+	address = normalizeAddress(address)
+	if address == "" {
+		return nil, ErrBlankAddress
+	}
+
 	if nType, ok := addressNet[address[0]]; ok {
 		return NewAddress(address, nType), nil
 	}
@@ -158,6 +322,36 @@ func NewAddressFromRaw(address string) (*Address, error) {
 	return nil, ErrInvalidAddress
 }
 
+// ParseAddress returns the Address represented by address, which may be in
+// raw (SB3KUBHATFCPV7UZQLWAQ2EUR6SIHBSBEOEMDDJJ), pretty
+// (SB3KUB-HATFCP-V7UZQL-WAQ2EU-R6SIHB-SBEOEM-DDJJ) or encoded hexadecimal
+// form. Unlike NewAddress it verifies the network byte and the checksum,
+// so it is suitable for user input.
+func ParseAddress(address string) (*Address, error) {
+	address = strings.TrimSpace(address)
+
+	if len(address) == addressEncodedLength {
+		if b, err := hex.DecodeString(address); err == nil {
+			address = base32.StdEncoding.EncodeToString(b)
+		}
+	}
+
+	b, err := decodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAddress(address, NetworkType(b[0])), nil
+}
+
+// ValidateAddress checks that address, in raw or pretty form, decodes to a
+// known network byte followed by a public key hash and a valid checksum.
+// It returns nil for a valid address.
+func ValidateAddress(address string) error {
+	_, err := decodeAddress(address)
+	return err
+}
+
 // Create an Address from a given raw address.
 func NewAddressFromPublicKey(pKey string, networkType NetworkType) (*Address, error) {
 	ad, err := generateEncodedAddress(pKey, networkType)
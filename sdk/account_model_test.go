@@ -6,6 +6,8 @@ package sdk
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +46,116 @@ func TestGenerateEncodedAddress(t *testing.T) {
 	assert.Nilf(t, err, "generateEncodedAddress returned error: %s", err)
 	assert.Equal(t, testEncodedAddress1, res)
 }
+
+func TestValidateAddress(t *testing.T) {
+	for _, address := range testAddressesForEncoded {
+		assert.Nil(t, ValidateAddress(address))
+		assert.Nil(t, ValidateAddress(NewAddress(address, MijinTest).Pretty()))
+		assert.Nil(t, ValidateAddress(strings.ToLower(address)))
+	}
+
+	for address, expected := range map[string]error{
+		"":   ErrBlankAddress,
+		" -": ErrBlankAddress,
+		"SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZS":   ErrInvalidAddressLength,
+		"SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSPA": ErrInvalidAddressLength,
+		"SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZS1":  ErrInvalidAddressEncoding,
+		"AARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSP":  ErrInvalidAddressNetwork,
+		"SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSQ":  ErrInvalidAddressChecksum,
+		"TARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSP":  ErrInvalidAddressChecksum,
+	} {
+		assert.Equal(t, expected, ValidateAddress(address), address)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	for nType, raw := range testAddressesForEncoded {
+		ad := NewAddress(raw, nType)
+
+		encoded, err := ad.Encoded()
+		assert.Nilf(t, err, "Address.Encoded returned error: %s", err)
+		assert.Len(t, encoded, 50)
+
+		for _, form := range []string{raw, ad.Pretty(), encoded, strings.ToLower(encoded), " " + raw + " "} {
+			parsed, err := ParseAddress(form)
+			assert.Nilf(t, err, "ParseAddress returned error for %s: %s", form, err)
+			assert.Equal(t, ad, parsed)
+		}
+
+		fromEncoded, err := NewAddressFromEncoded(encoded)
+		assert.Nil(t, err)
+		assert.True(t, ad.Equals(fromEncoded))
+	}
+
+	_, err := ParseAddress("SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSQ")
+	assert.Equal(t, ErrInvalidAddressChecksum, err)
+}
+
+func TestAddress_Pretty(t *testing.T) {
+	assert.Equal(t, "SARNAS-AS2BIA-B6LMFA-3FPMGB-PGIJGK-6IJETM-3ZSP", NewAddress("SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSP", MijinTest).Pretty())
+	assert.Equal(t, "SARNAS-AS2BIA-B6LMFA-3FPMGB-PGIJGK-6IJETM-3ZSP", NewAddress("SARNAS-AS2BIA-B6LMFA-3FPMGB-PGIJGK-6IJETM-3ZSP", MijinTest).Pretty())
+	assert.Equal(t, "SARNAS-AS", (&Address{MijinTest, "SARNASAS"}).Pretty())
+	assert.Equal(t, "", (&Address{MijinTest, ""}).Pretty())
+}
+
+func TestAddress_Equals(t *testing.T) {
+	ad := NewAddress("SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSP", MijinTest)
+
+	assert.True(t, ad.Equals(&Address{MijinTest, "sarnas-as2bia-b6lmfa-3fpmgb-pgijgk-6ijetm-3zsp"}))
+	assert.False(t, ad.Equals(NewAddress("MARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJE5K5RYU", Mijin)))
+	assert.False(t, ad.Equals(nil))
+	assert.True(t, (*Address)(nil).Equals(nil))
+}
+
+func TestAddress_IsValidFor(t *testing.T) {
+	for nType, raw := range testAddressesForEncoded {
+		ad := NewAddress(raw, nType)
+
+		for _, other := range []NetworkType{MainNet, TestNet, Mijin, MijinTest} {
+			assert.Equal(t, nType == other, ad.IsValidFor(other))
+		}
+	}
+
+	assert.False(t, NewAddress("SARNASAS2BIAB6LMFA3FPMGBPGIJGK6IJETM3ZSQ", MijinTest).IsValidFor(MijinTest))
+}
+
+func TestNewAddressFromRaw_Blank(t *testing.T) {
+	_, err := NewAddressFromRaw("")
+	assert.Equal(t, ErrBlankAddress, err)
+}
+
+func TestAccountInfo_ImportanceShare(t *testing.T) {
+	info := &AccountInfo{Importance: big.NewInt(25)}
+
+	assert.Equal(t, 0.25, info.ImportanceShare(big.NewInt(100)))
+	assert.Equal(t, float64(0), info.ImportanceShare(big.NewInt(0)))
+	assert.Equal(t, float64(0), (&AccountInfo{}).ImportanceShare(big.NewInt(100)))
+}
+
+func TestRankByImportance(t *testing.T) {
+	a := &AccountInfo{Importance: big.NewInt(10)}
+	b := &AccountInfo{Importance: big.NewInt(50)}
+	c := &AccountInfo{Importance: big.NewInt(10)}
+	d := &AccountInfo{}
+
+	ranks := RankByImportance([]*AccountInfo{a, b, nil, c, d, &AccountInfo{Importance: big.NewInt(30)}})
+
+	assert.Len(t, ranks, 5)
+	assert.Equal(t, b, ranks[0].Account)
+	assert.Equal(t, 1, ranks[0].Rank)
+	assert.Equal(t, 0.5, ranks[0].Share)
+	assert.Equal(t, 2, ranks[1].Rank)
+
+	// equal importance shares the rank, in input order
+	assert.Equal(t, a, ranks[2].Account)
+	assert.Equal(t, c, ranks[3].Account)
+	assert.Equal(t, 3, ranks[2].Rank)
+	assert.Equal(t, 3, ranks[3].Rank)
+	assert.Equal(t, 0.1, ranks[3].Share)
+
+	assert.Equal(t, d, ranks[4].Account)
+	assert.Equal(t, 5, ranks[4].Rank)
+	assert.Equal(t, float64(0), ranks[4].Share)
+
+	assert.Len(t, RankByImportance(nil), 0)
+}
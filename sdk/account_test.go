@@ -7,6 +7,8 @@ import (
 	"github.com/proximax-storage/proximax-utils-go/mock"
 	"github.com/proximax-storage/proximax-utils-go/tests"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -86,6 +88,177 @@ func TestAccountService_GetAccountInfo(t *testing.T) {
 	tests.ValidateStringers(t, account, acc)
 }
 
+func TestAccountService_GetBalance(t *testing.T) {
+	// a mosaic without namespace, so GetMosaic doesn't look up names
+	mosaicJson := strings.Replace(tplMosaic, "929036875,\n      2226345261", "0,\n      0", 1)
+
+	m := newSdkMock(0)
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress1),
+		RespBody: accountInfoJson,
+	})
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(mosaicRoute, testMosaicPathID),
+		RespBody: mosaicJson,
+	})
+	defer m.Close()
+
+	client := m.getTestNetClientUnsafe()
+	mosaicId := bigIntToMosaicId(uint64DTO{3646934825, 3576016193}.toBigInt())
+
+	balance, err := client.Account.GetBalance(ctx, &Address{MijinTest, nemTestAddress1}, mosaicId)
+	assert.Nilf(t, err, "AccountService.GetBalance returned error: %s", err)
+	assert.Equal(t, uint64DTO{3863990592, 95248}.toBigInt(), balance.Raw)
+	assert.Equal(t, int64(6), balance.Divisibility)
+	assert.Equal(t, "409090909.000000", balance.String())
+
+	// the divisibility is cached, the mosaic isn't fetched again
+	m.AddRouter(&mock.Router{
+		Path:         fmt.Sprintf(mosaicRoute, testMosaicPathID),
+		RespHttpCode: 500,
+	})
+
+	d, err := client.Mosaic.GetMosaicDivisibility(ctx, mosaicId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), d)
+
+	// a mosaic the account doesn't own
+	client.divisibilities.set(testMosaicIds[1], 0)
+
+	balance, err = client.Account.GetBalance(ctx, &Address{MijinTest, nemTestAddress1}, testMosaicIds[1])
+	assert.Nilf(t, err, "AccountService.GetBalance returned error: %s", err)
+	assert.Equal(t, big.NewInt(0), balance.Raw)
+	assert.True(t, balance.IsZero())
+}
+
+func TestAccountService_GetAccountLinkInfo(t *testing.T) {
+	linked := strings.Replace(accountInfoJson, "\"importance\":[", "\"accountType\": 1,\n      \"linkedAccountKey\": \""+publicKey1+"\",\n      \"importance\":[", 1)
+
+	m := newSdkMock(0)
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress1),
+		RespBody: linked,
+	})
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress2),
+		RespBody: accountInfoJson,
+	})
+	defer m.Close()
+
+	client := m.getTestNetClientUnsafe()
+
+	info, err := client.Account.GetAccountLinkInfo(ctx, &Address{MijinTest, nemTestAddress1})
+	assert.Nilf(t, err, "AccountService.GetAccountLinkInfo returned error: %s", err)
+	assert.Equal(t, MainAccount, info.AccountType)
+	assert.Equal(t, publicKey1, info.LinkedAccount.PublicKey)
+	assert.Equal(t, big.NewInt(409090909), info.Importance)
+	assert.True(t, info.IsLinked())
+	assert.False(t, info.CanHarvest())
+
+	info, err = client.Account.GetAccountLinkInfo(ctx, &Address{MijinTest, nemTestAddress2})
+	assert.Nil(t, err)
+	assert.Equal(t, UnlinkedAccount, info.AccountType)
+	assert.Nil(t, info.LinkedAccount)
+	assert.False(t, info.IsLinked())
+	assert.True(t, info.CanHarvest())
+
+	_, err = client.Account.GetAccountLinkInfo(ctx, nil)
+	assert.Equal(t, ErrNilAddress, err)
+}
+
+func TestAccountService_ResolvePublicAccount(t *testing.T) {
+	revealed := strings.Replace(accountInfoJson, "\"publicKeyHeight\":[  \n         0,", "\"publicKeyHeight\":[  \n         7,", 1)
+
+	m := newSdkMock(0)
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress1),
+		RespBody: revealed,
+	})
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress2),
+		RespBody: accountInfoJson,
+	})
+	defer m.Close()
+
+	client := m.getTestNetClientUnsafe()
+
+	acc, err := client.Account.ResolvePublicAccount(ctx, &Address{MijinTest, nemTestAddress1})
+	assert.Nilf(t, err, "AccountService.ResolvePublicAccount returned error: %s", err)
+	assert.Equal(t, "F3824119C9F8B9E81007CAA0EDD44F098458F14503D7C8D7C24F60AF11266E57", acc.PublicKey)
+	assert.Equal(t, nemTestAddress1, acc.Address.Address)
+
+	_, err = client.Account.ResolvePublicAccount(ctx, &Address{MijinTest, nemTestAddress2})
+	notRevealed, ok := err.(*PublicKeyNotRevealedError)
+	assert.True(t, ok, "expected a *PublicKeyNotRevealedError, got %v", err)
+	assert.Equal(t, nemTestAddress2, notRevealed.Address.Address)
+
+	_, err = client.Account.ResolvePublicAccount(ctx, &Address{MijinTest, "SDUP5PLHDXKBX3UU5Q52LAY4WYEKGEWC6IB3VBFM"})
+	_, ok = err.(*PublicKeyNotRevealedError)
+	assert.True(t, ok, "expected a *PublicKeyNotRevealedError for an unknown account, got %v", err)
+
+	// the resolved account is cached, even in pretty form
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(accountRoute, nemTestAddress1),
+		RespBody: accountInfoJson,
+	})
+
+	cached, err := client.Account.ResolvePublicAccount(ctx, &Address{MijinTest, "saonso-gfzzhn-eibryx-hdtdtb-r2ysax-ktitrf-hg2y"})
+	assert.Nil(t, err)
+	assert.Equal(t, acc, cached)
+}
+
+func TestAccountService_ResolvePublicAccounts(t *testing.T) {
+	m := newSdkMock(0)
+	m.AddRouter(&mock.Router{
+		Path:     accountsRoute,
+		RespBody: "[" + strings.Replace(accountInfoJson, "\"publicKeyHeight\":[  \n         0,", "\"publicKeyHeight\":[  \n         7,", 1) + "]",
+	})
+	defer m.Close()
+
+	client := m.getTestNetClientUnsafe()
+
+	accs, err := client.Account.ResolvePublicAccounts(ctx, []*Address{{MijinTest, nemTestAddress2}, {MijinTest, nemTestAddress1}})
+	assert.Nilf(t, err, "AccountService.ResolvePublicAccounts returned error: %s", err)
+	assert.Len(t, accs, 2)
+	assert.Nil(t, accs[0])
+	assert.Equal(t, "F3824119C9F8B9E81007CAA0EDD44F098458F14503D7C8D7C24F60AF11266E57", accs[1].PublicKey)
+
+	acc, err := client.Account.ResolvePublicAccount(ctx, &Address{MijinTest, nemTestAddress1})
+	assert.Nil(t, err)
+	assert.Equal(t, accs[1], acc)
+}
+
+func TestPublicAccountCache_Size(t *testing.T) {
+	c := &publicAccountCache{size: 2}
+
+	keys := []string{publicKey1, testNEMPublicKey, "F3824119C9F8B9E81007CAA0EDD44F098458F14503D7C8D7C24F60AF11266E57"}
+	accs := make([]*PublicAccount, len(keys))
+	for i, key := range keys {
+		acc, err := NewAccountFromPublicKey(key, MijinTest)
+		assert.Nil(t, err)
+		accs[i] = acc
+	}
+
+	add := func(acc *PublicAccount) {
+		assert.Equal(t, acc, c.add(&AccountInfo{Address: acc.Address, PublicKey: acc.PublicKey, PublicKeyHeight: big.NewInt(1)}))
+	}
+
+	add(accs[0])
+	add(accs[1])
+
+	// 0 becomes the most recently used, so 1 is evicted
+	_, ok := c.get(accs[0].Address)
+	assert.True(t, ok)
+	add(accs[2])
+
+	_, ok = c.get(accs[1].Address)
+	assert.False(t, ok)
+	acc, ok := c.get(accs[0].Address)
+	assert.True(t, ok)
+	assert.Equal(t, accs[0], acc)
+	assert.Equal(t, 2, c.lru.Len())
+}
+
 func TestAccountService_GetAccountsInfo(t *testing.T) {
 	mockServer.AddRouter(&mock.Router{
 		Path:     "/account",
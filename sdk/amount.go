@@ -0,0 +1,194 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"math/big"
+	"strings"
+)
+
+// maxDivisibility is the largest divisibility Catapult allows.
+const maxDivisibility = 6
+
+// Amount is an exact decimal quantity of a mosaic. Raw holds the amount in
+// the smallest unit of the mosaic, as stored on chain, and Divisibility the
+// number of decimal places of the mosaic, so 12345678 with divisibility 6
+// is 12.345678.
+type Amount struct {
+	MosaicId     *MosaicId
+	Divisibility int64
+	Raw          *big.Int
+}
+
+// NewAmount returns the Amount of raw smallest units of the mosaic.
+func NewAmount(mosaicId *MosaicId, raw *big.Int, divisibility int64) (*Amount, error) {
+	if mosaicId == nil {
+		return nil, ErrNilMosaicId
+	}
+	if raw == nil {
+		return nil, ErrNilMosaicAmount
+	}
+	if divisibility < 0 || divisibility > maxDivisibility {
+		return nil, ErrInvalidDivisibility
+	}
+
+	return &Amount{mosaicId, divisibility, new(big.Int).Set(raw)}, nil
+}
+
+// NewAmountFromMosaic returns the Amount of the mosaic.
+func NewAmountFromMosaic(mosaic *Mosaic, divisibility int64) (*Amount, error) {
+	if mosaic == nil {
+		return nil, ErrNilMosaicAmount
+	}
+
+	return NewAmount(mosaic.MosaicId, mosaic.Amount, divisibility)
+}
+
+// ParseAmount parses a decimal string such as "12.345678", "-0.5" or "100"
+// exactly. It fails with ErrInvalidAmount if s has more decimal places than
+// divisibility or is not a decimal number.
+func ParseAmount(mosaicId *MosaicId, s string, divisibility int64) (*Amount, error) {
+	if divisibility < 0 || divisibility > maxDivisibility {
+		return nil, ErrInvalidDivisibility
+	}
+
+	s = strings.TrimSpace(s)
+
+	neg := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	parts := strings.SplitN(s, ".", 2)
+	whole, frac := parts[0], ""
+	if len(parts) == 2 {
+		frac = parts[1]
+		if frac == "" {
+			return nil, ErrInvalidAmount
+		}
+	}
+
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) || int64(len(frac)) > divisibility {
+		return nil, ErrInvalidAmount
+	}
+
+	digits := whole + frac + strings.Repeat("0", int(divisibility)-len(frac))
+	raw, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, ErrInvalidAmount
+	}
+	if neg {
+		raw.Neg(raw)
+	}
+
+	return NewAmount(mosaicId, raw, divisibility)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the amount with exactly Divisibility decimal places,
+// e.g. "12.345678" or "-0.500000".
+func (a *Amount) String() string {
+	digits := new(big.Int).Abs(a.Raw).String()
+
+	d := int(a.Divisibility)
+	if len(digits) <= d {
+		digits = strings.Repeat("0", d-len(digits)+1) + digits
+	}
+
+	res := digits
+	if d > 0 {
+		res = digits[:len(digits)-d] + "." + digits[len(digits)-d:]
+	}
+	if a.Raw.Sign() < 0 {
+		res = "-" + res
+	}
+	return res
+}
+
+// Text returns the amount like String, without trailing zero decimals,
+// e.g. "12.3" or "100".
+func (a *Amount) Text() string {
+	s := a.String()
+	if a.Divisibility > 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Mosaic returns the amount as a Mosaic, to be used in transactions.
+func (a *Amount) Mosaic() *Mosaic {
+	return &Mosaic{a.MosaicId, new(big.Int).Set(a.Raw)}
+}
+
+func (a *Amount) compatible(b *Amount) error {
+	if b == nil {
+		return ErrNilMosaicAmount
+	}
+	if mosaicIdToBigInt(a.MosaicId).Cmp(mosaicIdToBigInt(b.MosaicId)) != 0 {
+		return ErrMosaicMismatch
+	}
+	if a.Divisibility != b.Divisibility {
+		return ErrInvalidDivisibility
+	}
+	return nil
+}
+
+// Add returns a+b. Both must be amounts of the same mosaic.
+func (a *Amount) Add(b *Amount) (*Amount, error) {
+	if err := a.compatible(b); err != nil {
+		return nil, err
+	}
+
+	return &Amount{a.MosaicId, a.Divisibility, new(big.Int).Add(a.Raw, b.Raw)}, nil
+}
+
+// Sub returns a-b. Both must be amounts of the same mosaic.
+func (a *Amount) Sub(b *Amount) (*Amount, error) {
+	if err := a.compatible(b); err != nil {
+		return nil, err
+	}
+
+	return &Amount{a.MosaicId, a.Divisibility, new(big.Int).Sub(a.Raw, b.Raw)}, nil
+}
+
+// Mul returns the amount multiplied by n.
+func (a *Amount) Mul(n int64) *Amount {
+	return &Amount{a.MosaicId, a.Divisibility, new(big.Int).Mul(a.Raw, big.NewInt(n))}
+}
+
+// Cmp compares a and b and returns -1, 0 or +1. Both must be amounts of the
+// same mosaic.
+func (a *Amount) Cmp(b *Amount) (int, error) {
+	if err := a.compatible(b); err != nil {
+		return 0, err
+	}
+
+	return a.Raw.Cmp(b.Raw), nil
+}
+
+// Equals reports whether b is the same amount of the same mosaic.
+func (a *Amount) Equals(b *Amount) bool {
+	c, err := a.Cmp(b)
+	return err == nil && c == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (a *Amount) Sign() int {
+	return a.Raw.Sign()
+}
+
+// IsZero reports whether the amount is zero.
+func (a *Amount) IsZero() bool {
+	return a.Raw.Sign() == 0
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	for s, raw := range map[string]string{
+		"12.345678":                  "12345678",
+		"12.3":                       "12300000",
+		"0.000001":                   "1",
+		".5":                         "500000",
+		"+7":                         "7000000",
+		"-0.5":                       "-500000",
+		" 100 ":                      "100000000",
+		"00012.00000":                "12000000",
+		"9223372036854775807.999999": "9223372036854775807999999",
+	} {
+		a, err := ParseAmount(XemMosaicId, s, XemDivisibility)
+		assert.Nilf(t, err, "ParseAmount returned error for %q: %s", s, err)
+
+		expected, _ := new(big.Int).SetString(raw, 10)
+		assert.Equal(t, expected, a.Raw, s)
+	}
+
+	for _, s := range []string{"", ".", "1.", "-", "1.2345678", "1,5", "1e6", "0x10", "1.2.3", "--1"} {
+		_, err := ParseAmount(XemMosaicId, s, XemDivisibility)
+		assert.Equal(t, ErrInvalidAmount, err, s)
+	}
+
+	a, err := ParseAmount(XemMosaicId, "42", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(42), a.Raw)
+
+	_, err = ParseAmount(XemMosaicId, "4.2", 0)
+	assert.Equal(t, ErrInvalidAmount, err)
+
+	_, err = ParseAmount(XemMosaicId, "1", 7)
+	assert.Equal(t, ErrInvalidDivisibility, err)
+}
+
+func TestAmount_String(t *testing.T) {
+	for _, tc := range []struct {
+		raw          int64
+		divisibility int64
+		str, text    string
+	}{
+		{12345678, 6, "12.345678", "12.345678"},
+		{12300000, 6, "12.300000", "12.3"},
+		{1, 6, "0.000001", "0.000001"},
+		{0, 6, "0.000000", "0"},
+		{-500000, 6, "-0.500000", "-0.5"},
+		{100, 0, "100", "100"},
+		{100, 2, "1.00", "1"},
+	} {
+		a, err := NewAmount(XemMosaicId, big.NewInt(tc.raw), tc.divisibility)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.str, a.String())
+		assert.Equal(t, tc.text, a.Text())
+
+		parsed, err := ParseAmount(XemMosaicId, a.String(), tc.divisibility)
+		assert.Nil(t, err)
+		assert.True(t, a.Equals(parsed))
+	}
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	a, _ := ParseAmount(XemMosaicId, "10.5", XemDivisibility)
+	b, _ := ParseAmount(XemMosaicId, "0.000001", XemDivisibility)
+
+	sum, err := a.Add(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.500001", sum.String())
+
+	diff, err := b.Sub(a)
+	assert.Nil(t, err)
+	assert.Equal(t, "-10.499999", diff.String())
+	assert.Equal(t, -1, diff.Sign())
+
+	assert.Equal(t, "31.500000", a.Mul(3).String())
+	assert.Equal(t, "10.500000", a.String(), "operands are not modified")
+
+	c, err := a.Cmp(b)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, c)
+
+	zero, _ := a.Sub(a)
+	assert.True(t, zero.IsZero())
+
+	other, _ := NewAmount(testMosaicIds[1], big.NewInt(1), XemDivisibility)
+	_, err = a.Add(other)
+	assert.Equal(t, ErrMosaicMismatch, err)
+	_, err = a.Cmp(other)
+	assert.Equal(t, ErrMosaicMismatch, err)
+	assert.False(t, a.Equals(other))
+
+	coarse, _ := NewAmount(XemMosaicId, big.NewInt(1), 2)
+	_, err = a.Sub(coarse)
+	assert.Equal(t, ErrInvalidDivisibility, err)
+
+	assert.Equal(t, &Mosaic{XemMosaicId, big.NewInt(10500000)}, a.Mosaic())
+}
+
+func TestXemRelative(t *testing.T) {
+	assert.Equal(t, big.NewInt(10000000), XemRelative(10).Amount)
+
+	expected := new(big.Int).Mul(big.NewInt(1<<50), big.NewInt(1000000))
+	assert.Equal(t, expected, XemRelative(1<<50).Amount)
+}
@@ -0,0 +1,372 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"golang.org/x/net/context"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultAnalyticsBucketSize  = 1000
+	defaultAnalyticsTopHolders  = 10
+	defaultAnalyticsConcurrency = 4
+)
+
+// MosaicAnalyticsOptions configures MosaicService.GetMosaicAnalytics; zero
+// values mean defaults.
+type MosaicAnalyticsOptions struct {
+	// FromHeight is the first block scanned, the height the mosaic was
+	// created at by default. The holder distribution is only complete when
+	// the scan starts there.
+	FromHeight *big.Int
+	// ToHeight is the last block scanned, the chain height by default.
+	ToHeight *big.Int
+	// BucketSize is the number of blocks of a volume bucket, 1000 by default.
+	BucketSize int64
+	// TopHolders is the number of largest holders reported, 10 by default.
+	TopHolders int
+	// Concurrency is the number of blocks requested at once, 4 by default.
+	Concurrency int
+}
+
+// SupplyChange is a MosaicSupplyChange transaction of the mosaic.
+type SupplyChange struct {
+	Height *big.Int
+	Hash   Hash
+	Type   MosaicSupplyType
+	Delta  *big.Int
+	// Supply after the change. It is derived from the current supply, so it
+	// is only known when the scan reaches the chain height.
+	Supply *big.Int
+}
+
+// VolumeBucket is the transfer volume of the mosaic over a range of blocks.
+type VolumeBucket struct {
+	FromHeight *big.Int
+	ToHeight   *big.Int
+	Transfers  int
+	Amount     *big.Int
+}
+
+// Holder is an account and the amount of the mosaic it holds.
+type Holder struct {
+	Address *Address
+	Amount  *big.Int
+	// Share of the amounts of all holders, between 0 and 1.
+	Share float64
+}
+
+// HolderDistribution is the distribution of the mosaic among the accounts,
+// reconstructed from the transfers and supply changes scanned. Locked funds
+// are not taken into account.
+type HolderDistribution struct {
+	// Holders is the number of accounts holding a positive amount.
+	Holders int
+	// Top are the largest holders, largest first.
+	Top []*Holder
+	// Gini coefficient of the amounts held, 0 when all holders hold the same
+	// amount and close to 1 when a single one holds everything.
+	Gini float64
+}
+
+// MosaicAnalytics are the metrics of a mosaic over the blocks scanned.
+type MosaicAnalytics struct {
+	MosaicId *MosaicId
+	// Supply is the current supply of the mosaic.
+	Supply        *big.Int
+	FromHeight    *big.Int
+	ToHeight      *big.Int
+	SupplyChanges []*SupplyChange
+	Volume        []*VolumeBucket
+	Distribution  *HolderDistribution
+}
+
+// GetMosaicAnalytics scans the blocks with the transactions of the mosaic
+// and returns its supply history, transfer volume and holder distribution.
+// Transactions of aggregates are included.
+func (ref *MosaicService) GetMosaicAnalytics(ctx context.Context, mosaicId *MosaicId, opts *MosaicAnalyticsOptions) (*MosaicAnalytics, error) {
+	if mosaicId == nil {
+		return nil, ErrNilMosaicId
+	}
+
+	o := MosaicAnalyticsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.BucketSize <= 0 {
+		o.BucketSize = defaultAnalyticsBucketSize
+	}
+	if o.TopHolders <= 0 {
+		o.TopHolders = defaultAnalyticsTopHolders
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultAnalyticsConcurrency
+	}
+
+	mscInfo, err := ref.GetMosaic(ctx, mosaicId)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := ref.client.Blockchain.GetBlockchainHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := o.FromHeight, o.ToHeight
+	if from == nil {
+		from = mscInfo.Height
+	}
+	if from == nil || from.Sign() <= 0 {
+		from = big.NewInt(1)
+	}
+	if to == nil || to.Cmp(height) > 0 {
+		to = height
+	}
+	if from.Cmp(to) > 0 {
+		return nil, ErrInvalidHeightRange
+	}
+
+	a := &mosaicAnalyzer{
+		mosaicId: mosaicId,
+		bucket:   o.BucketSize,
+		from:     from,
+		balances: make(map[string]*Holder),
+		result: &MosaicAnalytics{
+			MosaicId:      mosaicId,
+			Supply:        mscInfo.Supply,
+			FromHeight:    from,
+			ToHeight:      to,
+			SupplyChanges: make([]*SupplyChange, 0),
+			Volume:        volumeBuckets(from, to, o.BucketSize),
+		},
+	}
+
+	if err := ref.scanBlocks(ctx, from, to, o.Concurrency, a.block); err != nil {
+		return nil, err
+	}
+
+	if to.Cmp(height) == 0 && mscInfo.Supply != nil {
+		a.supplies(mscInfo.Supply)
+	}
+
+	a.result.Distribution = distribution(a.balances, o.TopHolders)
+
+	return a.result, nil
+}
+
+// scanBlocks calls fn with the transactions of the blocks from to to, in
+// order, requesting up to concurrency blocks at once.
+func (ref *MosaicService) scanBlocks(ctx context.Context, from, to *big.Int, concurrency int, fn func(height *big.Int, txs []Transaction)) error {
+	height := new(big.Int).Set(from)
+
+	for height.Cmp(to) <= 0 {
+		n := new(big.Int).Sub(to, height).Int64() + 1
+		if n > int64(concurrency) {
+			n = int64(concurrency)
+		}
+
+		var (
+			txs  = make([][]Transaction, n)
+			errs = make([]error, n)
+			wg   sync.WaitGroup
+		)
+
+		for i := int64(0); i < n; i++ {
+			wg.Add(1)
+			go func(i int64) {
+				defer wg.Done()
+				txs[i], errs[i] = ref.client.Blockchain.GetAllBlockTransactions(ctx, new(big.Int).Add(height, big.NewInt(i)), 0)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := int64(0); i < n; i++ {
+			if errs[i] != nil {
+				return errs[i]
+			}
+
+			fn(new(big.Int).Add(height, big.NewInt(i)), txs[i])
+		}
+
+		height.Add(height, big.NewInt(n))
+	}
+
+	return nil
+}
+
+type mosaicAnalyzer struct {
+	mosaicId *MosaicId
+	bucket   int64
+	from     *big.Int
+	balances map[string]*Holder
+	result   *MosaicAnalytics
+}
+
+func (a *mosaicAnalyzer) block(height *big.Int, txs []Transaction) {
+	for _, tx := range txs {
+		a.transaction(height, tx)
+	}
+}
+
+func (a *mosaicAnalyzer) transaction(height *big.Int, tx Transaction) {
+	switch tx := tx.(type) {
+	case *AggregateTransaction:
+		for _, inner := range tx.InnerTransactions {
+			a.transaction(height, inner)
+		}
+
+	case *MosaicSupplyChangeTransaction:
+		if !a.isMosaic(tx.MosaicId) || tx.Delta == nil {
+			return
+		}
+
+		change := &SupplyChange{
+			Height: height,
+			Type:   tx.MosaicSupplyType,
+			Delta:  tx.Delta,
+		}
+		if tx.TransactionInfo != nil {
+			change.Hash = tx.TransactionInfo.Hash
+		}
+		a.result.SupplyChanges = append(a.result.SupplyChanges, change)
+
+		if tx.Signer == nil {
+			return
+		}
+		if tx.MosaicSupplyType == Increase {
+			a.credit(tx.Signer.Address, tx.Delta)
+		} else {
+			a.credit(tx.Signer.Address, new(big.Int).Neg(tx.Delta))
+		}
+
+	case *TransferTransaction:
+		for _, m := range tx.Mosaics {
+			if m == nil || !a.isMosaic(m.MosaicId) || m.Amount == nil {
+				continue
+			}
+
+			b := a.volume(height)
+			b.Transfers++
+			b.Amount.Add(b.Amount, m.Amount)
+
+			if tx.Signer != nil {
+				a.credit(tx.Signer.Address, new(big.Int).Neg(m.Amount))
+			}
+			a.credit(tx.Recipient, m.Amount)
+		}
+	}
+}
+
+func (a *mosaicAnalyzer) isMosaic(mosaicId *MosaicId) bool {
+	return mosaicId != nil && mosaicIdToBigInt(mosaicId).Cmp(mosaicIdToBigInt(a.mosaicId)) == 0
+}
+
+// volume returns the bucket of height.
+func (a *mosaicAnalyzer) volume(height *big.Int) *VolumeBucket {
+	return a.result.Volume[new(big.Int).Sub(height, a.from).Int64()/a.bucket]
+}
+
+// volumeBuckets returns the empty buckets of size blocks from to to, the
+// last one ending at to.
+func volumeBuckets(from, to *big.Int, size int64) []*VolumeBucket {
+	buckets := make([]*VolumeBucket, 0)
+
+	for start := new(big.Int).Set(from); start.Cmp(to) <= 0; start = new(big.Int).Add(start, big.NewInt(size)) {
+		end := new(big.Int).Add(start, big.NewInt(size-1))
+		if end.Cmp(to) > 0 {
+			end = new(big.Int).Set(to)
+		}
+
+		buckets = append(buckets, &VolumeBucket{
+			FromHeight: start,
+			ToHeight:   end,
+			Amount:     big.NewInt(0),
+		})
+	}
+
+	return buckets
+}
+
+func (a *mosaicAnalyzer) credit(address *Address, amount *big.Int) {
+	if address == nil {
+		return
+	}
+
+	key := normalizeAddress(address.Address)
+	h, ok := a.balances[key]
+	if !ok {
+		h = &Holder{Address: address, Amount: big.NewInt(0)}
+		a.balances[key] = h
+	}
+
+	h.Amount.Add(h.Amount, amount)
+}
+
+// supplies sets the supply after each change, going back from the current
+// supply.
+func (a *mosaicAnalyzer) supplies(current *big.Int) {
+	supply := new(big.Int).Set(current)
+
+	for i := len(a.result.SupplyChanges) - 1; i >= 0; i-- {
+		change := a.result.SupplyChanges[i]
+		change.Supply = new(big.Int).Set(supply)
+
+		if change.Type == Increase {
+			supply.Sub(supply, change.Delta)
+		} else {
+			supply.Add(supply, change.Delta)
+		}
+	}
+}
+
+// distribution ranks the holders with a positive amount and computes the
+// Gini coefficient of their amounts.
+func distribution(balances map[string]*Holder, top int) *HolderDistribution {
+	holders := make([]*Holder, 0, len(balances))
+	total := big.NewInt(0)
+
+	for _, h := range balances {
+		if h.Amount.Sign() > 0 {
+			holders = append(holders, h)
+			total.Add(total, h.Amount)
+		}
+	}
+
+	sort.Slice(holders, func(i, j int) bool {
+		if c := holders[i].Amount.Cmp(holders[j].Amount); c != 0 {
+			return c > 0
+		}
+		return holders[i].Address.Address < holders[j].Address.Address
+	})
+
+	d := &HolderDistribution{Holders: len(holders), Top: make([]*Holder, 0, top)}
+	if len(holders) == 0 {
+		return d
+	}
+
+	totalF, _ := new(big.Float).SetInt(total).Float64()
+
+	// G = 2 * sum(i * x_i) / (n * sum(x_i)) - (n + 1) / n, with x ascending
+	// and i from 1
+	n := float64(len(holders))
+	weighted := 0.0
+	for i, h := range holders {
+		amount, _ := new(big.Float).SetInt(h.Amount).Float64()
+		h.Share = amount / totalF
+		weighted += (n - float64(i)) * amount
+	}
+	d.Gini = 2*weighted/(n*totalF) - (n+1)/n
+
+	if len(holders) < top {
+		top = len(holders)
+	}
+	d.Top = append(d.Top, holders[:top]...)
+
+	return d
+}
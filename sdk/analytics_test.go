@@ -0,0 +1,121 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func analyticsTestSupplyChange(height int, signer string, direction MosaicSupplyType, delta uint64) string {
+	return fmt.Sprintf(`{
+	"meta": {"height": [%d, 0], "hash": "%064X", "merkleComponentHash": "%064X", "index": 0, "id": "%024X"},
+	"transaction": {
+		"signer": "%s",
+		"version": 36866,
+		"type": 16973,
+		"fee": [0, 0],
+		"deadline": [1, 0],
+		"mosaicId": [3646934825, 3576016193],
+		"direction": %d,
+		"delta": [%d, 0]
+	}
+}`, height, height, height, height, signer, direction, delta)
+}
+
+func TestMosaicService_GetMosaicAnalytics(t *testing.T) {
+	a, err := NewAccountFromPublicKey(testNEMPublicKey, MijinTest)
+	assert.Nil(t, err)
+	b, err := NewAccountFromPublicKey(publicKey1, MijinTest)
+	assert.Nil(t, err)
+	c := &Address{MijinTest, "SDUP5PLHDXKBX3UU5Q52LAY4WYEKGEWC6IB3VBFM"}
+
+	xem := testMosaicId
+	other := testMosaicIds[1]
+
+	blocks := map[int][]string{
+		1: {analyticsTestSupplyChange(1, a.PublicKey, Increase, 1000)},
+		2: {
+			testTransferJSON(t, 2, a.PublicKey, b.Address, "", &Mosaic{xem, big.NewInt(300)}, &Mosaic{other, big.NewInt(7)}),
+			testTransferJSON(t, 2, a.PublicKey, c, "", &Mosaic{xem, big.NewInt(100)}),
+		},
+		3: {
+			analyticsTestSupplyChange(3, a.PublicKey, Decrease, 200),
+			testTransferJSON(t, 3, b.PublicKey, c, "", &Mosaic{xem, big.NewInt(50)}),
+		},
+	}
+
+	// a mosaic without namespace, created at height 1
+	mosaicJson := strings.Replace(tplMosaic, "929036875,\n      2226345261", "0,\n      0", 1)
+	supply := uint64DTO{3403414400, 2095475}.toBigInt()
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(mosaicRoute, testMosaicPathID),
+		RespBody: mosaicJson,
+	})
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     blockHeightRoute,
+		RespBody: `{"height": [6, 0]}`,
+	})
+	for height := 1; height <= 6; height++ {
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(blockGetTransactionRoute, height),
+			RespBody: "[" + strings.Join(blocks[height], ",") + "]",
+		})
+	}
+
+	client := m.getTestNetClientUnsafe()
+
+	analytics, err := client.Mosaic.GetMosaicAnalytics(ctx, xem, &MosaicAnalyticsOptions{BucketSize: 2, TopHolders: 2, Concurrency: 3})
+	assert.Nilf(t, err, "MosaicService.GetMosaicAnalytics returned error: %s", err)
+
+	assert.Equal(t, supply, analytics.Supply)
+	assert.Equal(t, big.NewInt(1), analytics.FromHeight)
+	assert.Equal(t, big.NewInt(6), analytics.ToHeight)
+
+	assert.Len(t, analytics.SupplyChanges, 2)
+	assert.Equal(t, Increase, analytics.SupplyChanges[0].Type)
+	assert.Equal(t, big.NewInt(1), analytics.SupplyChanges[0].Height)
+	assert.Equal(t, new(big.Int).Add(supply, big.NewInt(200)), analytics.SupplyChanges[0].Supply)
+	assert.Equal(t, big.NewInt(200), analytics.SupplyChanges[1].Delta)
+	assert.Equal(t, supply, analytics.SupplyChanges[1].Supply)
+	assert.Equal(t, Hash(fmt.Sprintf("%064X", 3)), analytics.SupplyChanges[1].Hash)
+
+	// blocks 5 and 6 hold no transfer, but still have their bucket
+	assert.Equal(t, []*VolumeBucket{
+		{big.NewInt(1), big.NewInt(2), 2, big.NewInt(400)},
+		{big.NewInt(3), big.NewInt(4), 1, big.NewInt(50)},
+		{big.NewInt(5), big.NewInt(6), 0, big.NewInt(0)},
+	}, analytics.Volume)
+
+	// a holds 400, b 250 and c 150
+	d := analytics.Distribution
+	assert.Equal(t, 3, d.Holders)
+	assert.Len(t, d.Top, 2)
+	assert.Equal(t, a.Address.Address, d.Top[0].Address.Address)
+	assert.Equal(t, big.NewInt(400), d.Top[0].Amount)
+	assert.Equal(t, 0.5, d.Top[0].Share)
+	assert.Equal(t, big.NewInt(250), d.Top[1].Amount)
+	assert.InDelta(t, 0.208333, d.Gini, 1e-6)
+
+	// a range below the chain height leaves the supply after the changes unknown
+	analytics, err = client.Mosaic.GetMosaicAnalytics(ctx, xem, &MosaicAnalyticsOptions{FromHeight: big.NewInt(3), ToHeight: big.NewInt(3)})
+	assert.Nil(t, err)
+	assert.Len(t, analytics.SupplyChanges, 1)
+	assert.Nil(t, analytics.SupplyChanges[0].Supply)
+	// only c received within the range
+	assert.Len(t, analytics.Volume, 1)
+	assert.Equal(t, 1, analytics.Distribution.Holders)
+	assert.Equal(t, float64(0), analytics.Distribution.Gini)
+
+	_, err = client.Mosaic.GetMosaicAnalytics(ctx, xem, &MosaicAnalyticsOptions{FromHeight: big.NewInt(4), ToHeight: big.NewInt(3)})
+	assert.Equal(t, ErrInvalidHeightRange, err)
+}
@@ -0,0 +1,221 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"net"
+	"sync"
+)
+
+const (
+	defaultBatchSize        = 100
+	defaultBatchConcurrency = 4
+)
+
+// BatchConfig limits the requests which post lists of ids, such as
+// AccountService.GetAccountsInfo. Longer lists are split into chunks of
+// Size ids, requested concurrently.
+type BatchConfig struct {
+	// Size is the maximum number of ids per request, 100 by default.
+	Size int
+	// Concurrency is the maximum number of requests in flight, 4 by default.
+	Concurrency int
+}
+
+// BatchFailure is an id whose request failed.
+type BatchFailure struct {
+	Id  string
+	Err error
+}
+
+// BatchError is returned along with the results of a batch request when the
+// requests of some of its ids failed. The ids of a chunk failing for a reason
+// which may be specific to some of them, such as a 4xx response, are
+// requested again one by one, so Failures lists, in input order, the ids
+// which failed on their own.
+type BatchError struct {
+	Failures []*BatchFailure
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch request failed for %d ids, first %s: %s", len(e.Failures), e.Failures[0].Id, e.Failures[0].Err)
+}
+
+func (c *Client) batchLimits() (size, concurrency int) {
+	size, concurrency = defaultBatchSize, defaultBatchConcurrency
+
+	if b := c.config.Batch; b != nil {
+		if b.Size > 0 {
+			size = b.Size
+		}
+		if b.Concurrency > 0 {
+			concurrency = b.Concurrency
+		}
+	}
+	return
+}
+
+// doBatch calls fn for consecutive chunks of ids, running at most the
+// configured concurrency at a time. fn gets the offset of its chunk in ids,
+// so it can store the results at the index of their id. When the request of
+// a chunk fails for a reason which may be specific to some of its ids, fn is
+// called again for each of them; transport errors, 5xx responses and context
+// errors fail the whole chunk at once.
+// When every id fails the first error is returned, so a short list fails as
+// a single request would; when only some do, a *BatchError reports them.
+func (c *Client) doBatch(ctx context.Context, ids []string, fn func(ctx context.Context, offset int, ids []string) error) error {
+	size, concurrency := c.batchLimits()
+
+	var (
+		n        = (len(ids) + size - 1) / size
+		failures = make([][]*BatchFailure, n)
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+	)
+
+	for i := 0; i < n; i++ {
+		offset := i * size
+		chunk := ids[offset:minInt(offset+size, len(ids))]
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			failures[i] = batchFailures(chunk, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(ctx, offset, chunk); err != nil {
+				c.log.Warn("rest batch request failed", "offset", offset, "ids", len(chunk), "err", err)
+				failures[i] = c.retryBatch(ctx, offset, chunk, err, fn)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	batchErr := &BatchError{}
+	for _, f := range failures {
+		batchErr.Failures = append(batchErr.Failures, f...)
+	}
+
+	switch len(batchErr.Failures) {
+	case 0:
+		return nil
+	case len(ids):
+		return batchErr.Failures[0].Err
+	default:
+		return batchErr
+	}
+}
+
+// retryBatch calls fn for each id of a failed chunk and returns the ids which fail again.
+func (c *Client) retryBatch(ctx context.Context, offset int, ids []string, err error, fn func(ctx context.Context, offset int, ids []string) error) []*BatchFailure {
+	if len(ids) == 1 || !batchSplittable(ctx, err) {
+		return batchFailures(ids, err)
+	}
+
+	failures := make([]*BatchFailure, 0)
+
+	for i, id := range ids {
+		if ctx.Err() != nil {
+			failures = append(failures, &BatchFailure{id, ctx.Err()})
+			continue
+		}
+
+		if err := fn(ctx, offset+i, ids[i:i+1]); err != nil {
+			failures = append(failures, &BatchFailure{id, err})
+		}
+	}
+
+	if len(failures) > 0 {
+		c.log.Warn("rest batch retry failed", "offset", offset, "ids", len(failures), "err", failures[0].Err)
+	}
+
+	return failures
+}
+
+// batchSplittable reports whether err, the error of a chunk, may be caused by
+// some of its ids only. The node being unreachable or failing, and the
+// context being done, concern every id.
+func batchSplittable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	switch e := err.(type) {
+	case net.Error:
+		// *url.Error of the http client included
+		return false
+	case *responseStatusError:
+		return e.statusCode < 500
+	}
+
+	return true
+}
+
+func batchFailures(ids []string, err error) []*BatchFailure {
+	failures := make([]*BatchFailure, len(ids))
+	for i, id := range ids {
+		failures[i] = &BatchFailure{id, err}
+	}
+	return failures
+}
+
+// batchMatch matches the results of a chunk with its ids; keys[j] are the forms
+// of id result j is known by. It returns the index of the result of each id,
+// -1 for none, and the indexes of the results which match no id, as the node
+// may answer with a form of id which was not requested.
+func batchMatch(ids []string, keys [][]string) (byId []int, unmatched []int) {
+	index := make(map[string]int, len(keys))
+	for j, ks := range keys {
+		for _, k := range ks {
+			index[k] = j
+		}
+	}
+
+	matched := make([]bool, len(keys))
+	byId = make([]int, len(ids))
+
+	for i, id := range ids {
+		j, ok := index[id]
+		if !ok {
+			byId[i] = -1
+			continue
+		}
+
+		byId[i] = j
+		matched[j] = true
+	}
+
+	for j := range keys {
+		if !matched[j] {
+			unmatched = append(unmatched, j)
+		}
+	}
+
+	return byId, unmatched
+}
+
+// batchFailed reports whether err returned by doBatch leaves no result.
+func batchFailed(err error) bool {
+	_, partial := err.(*BatchError)
+	return err != nil && !partial
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
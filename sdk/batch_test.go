@@ -0,0 +1,213 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func batchTestIds(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	return ids
+}
+
+func batchTestClient(m *sdkMock, batch *BatchConfig) *Client {
+	client := m.getTestNetClientUnsafe()
+	client.config.Batch = batch
+
+	return client
+}
+
+func TestClient_doBatch(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	client := batchTestClient(m, &BatchConfig{Size: 10, Concurrency: 3})
+	ids := batchTestIds(95)
+
+	var (
+		mu       sync.Mutex
+		chunks   = make(map[int]int)
+		inFlight int32
+		maxIn    int32
+	)
+
+	err := client.doBatch(ctx, ids, func(ctx context.Context, offset int, chunk []string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxIn)
+			if n <= m || atomic.CompareAndSwapInt32(&maxIn, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Equal(t, ids[offset], chunk[0])
+
+		mu.Lock()
+		chunks[offset] = len(chunk)
+		mu.Unlock()
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, chunks, 10)
+	assert.Equal(t, 5, chunks[90])
+	assert.Equal(t, 10, chunks[0])
+	assert.True(t, maxIn <= 3, "at most 3 chunks in flight, got %d", maxIn)
+}
+
+func TestClient_doBatchErrors(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	client := batchTestClient(m, &BatchConfig{Size: 2, Concurrency: 2})
+	ids := batchTestIds(6)
+	errChunk := errors.New("chunk failed")
+
+	t.Run("partial", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			retried []string
+		)
+
+		// the chunk of ids 2 and 3 fails, then only id 3 fails on its own
+		err := client.doBatch(ctx, ids, func(ctx context.Context, offset int, chunk []string) error {
+			if len(chunk) == 1 {
+				mu.Lock()
+				retried = append(retried, chunk[0])
+				mu.Unlock()
+			}
+
+			if offset == 2 && len(chunk) == 2 || chunk[0] == "3" {
+				return errChunk
+			}
+			return nil
+		})
+
+		batchErr, ok := err.(*BatchError)
+		assert.True(t, ok)
+		assert.Equal(t, []*BatchFailure{{"3", errChunk}}, batchErr.Failures)
+		assert.False(t, batchFailed(err))
+		assert.Equal(t, []string{"2", "3"}, retried)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		err := client.doBatch(ctx, ids, func(ctx context.Context, offset int, chunk []string) error {
+			return errChunk
+		})
+
+		assert.Equal(t, errChunk, err)
+		assert.True(t, batchFailed(err))
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		for _, errNode := range []error{
+			&responseStatusError{503, "service unavailable"},
+			&url.Error{Op: "Post", URL: "http://localhost:3000", Err: errors.New("connection refused")},
+		} {
+			var calls int32
+
+			// the chunks fail as a whole, without a request per id
+			err := client.doBatch(ctx, ids, func(ctx context.Context, offset int, chunk []string) error {
+				atomic.AddInt32(&calls, 1)
+				return errNode
+			})
+
+			assert.Equal(t, errNode, err)
+			assert.Equal(t, int32(3), calls)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := client.doBatch(ctx, ids, func(ctx context.Context, offset int, chunk []string) error {
+			return ctx.Err()
+		})
+
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestBatchMatch(t *testing.T) {
+	byId, unmatched := batchMatch([]string{"A", "B", "C", "A"}, [][]string{{"C"}, {"X", "A"}, {"Y"}, nil})
+
+	assert.Equal(t, []int{1, -1, 0, 1}, byId)
+	assert.Equal(t, []int{2, 3}, unmatched)
+}
+
+func batchTestNamespaceName(id int) string {
+	return fmt.Sprintf(`{"namespaceId": [%d, 0], "name": "ns%d", "parentId": [0, 0]}`, id, id)
+}
+
+func TestNamespaceService_GetNamespaceNamesBatch(t *testing.T) {
+	// the names of the requested namespaces in reverse order, leaving out
+	// namespace 4 and adding namespace 99, which was not requested
+	names := make([]string, 0)
+	for _, id := range []int{99, 6, 5, 3, 2, 1} {
+		names = append(names, batchTestNamespaceName(id))
+	}
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     namespaceNamesRoute,
+		RespBody: "[" + strings.Join(names, ",") + "]",
+	})
+	defer m.Close()
+
+	nsIds := make([]*NamespaceId, 6)
+	for i := range nsIds {
+		nsIds[i] = bigIntToNamespaceId(big.NewInt(int64(i + 1)))
+	}
+
+	nsNames, err := m.getTestNetClientUnsafe().Namespace.GetNamespaceNames(ctx, nsIds)
+	assert.Nilf(t, err, "NamespaceService.GetNamespaceNames returned error: %s", err)
+
+	expected := []int64{1, 2, 3, 5, 6, 99}
+	assert.Len(t, nsNames, len(expected))
+	for i, nsName := range nsNames {
+		assert.Equal(t, fmt.Sprintf("ns%d", expected[i]), nsName.Name)
+		assert.Equal(t, big.NewInt(expected[i]), namespaceIdToBigInt(nsName.NamespaceId))
+	}
+}
+
+func TestNamespaceService_GetNamespaceNamesBatchFailed(t *testing.T) {
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:         namespaceNamesRoute,
+		RespHttpCode: 409,
+	})
+	defer m.Close()
+
+	client := batchTestClient(m, &BatchConfig{Size: 2})
+
+	nsIds := make([]*NamespaceId, 5)
+	for i := range nsIds {
+		nsIds[i] = bigIntToNamespaceId(big.NewInt(int64(i + 1)))
+	}
+
+	// every chunk and every id fails, as a single request would
+	nsNames, err := client.Namespace.GetNamespaceNames(ctx, nsIds)
+	assert.NotNil(t, err)
+	assert.True(t, batchFailed(err))
+	assert.Nil(t, nsNames)
+}
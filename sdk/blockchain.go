@@ -15,6 +15,8 @@ import (
 
 type BlockchainService service
 
+const defaultBlockTransactionsPageSize = 100
+
 // Get Block Height
 func (b *BlockchainService) GetBlockByHeight(ctx context.Context, height *big.Int) (*BlockInfo, error) {
 	if height == nil || height.Int64() == 0 {
@@ -59,6 +61,71 @@ func (b *BlockchainService) GetBlockTransactions(ctx context.Context, height *bi
 	return MapTransactions(&data)
 }
 
+// GetBlockTransactionsPage returns a page of the transactions of a block.
+// The node returns at most a page of transactions at a time, so
+// GetBlockTransactions may miss some of the transactions of a full block.
+func (b *BlockchainService) GetBlockTransactionsPage(ctx context.Context, height *big.Int, opt *BlockTransactionsOption) ([]Transaction, error) {
+	if height == nil || height.Int64() == 0 {
+		return nil, ErrNilOrZeroHeight
+	}
+
+	u, err := addOptions(fmt.Sprintf(blockGetTransactionRoute, height), opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+
+	resp, err := b.client.DoNewRequest(ctx, http.MethodGet, u, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = handleResponseStatusCode(resp, map[int]error{404: ErrResourceNotFound, 409: ErrArgumentNotValid}); err != nil {
+		return nil, err
+	}
+
+	return MapTransactions(&data)
+}
+
+// GetAllBlockTransactions returns all the transactions of a block, requested
+// pageSize at a time, 100 if pageSize is not positive.
+func (b *BlockchainService) GetAllBlockTransactions(ctx context.Context, height *big.Int, pageSize int) ([]Transaction, error) {
+	if pageSize <= 0 {
+		pageSize = defaultBlockTransactionsPageSize
+	}
+
+	txs := make([]Transaction, 0)
+	opt := &BlockTransactionsOption{PageSize: pageSize}
+
+	for {
+		page, err := b.GetBlockTransactionsPage(ctx, height, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		var last string
+		if len(page) > 0 {
+			if info := page[len(page)-1].GetAbstractTransaction().TransactionInfo; info != nil {
+				last = info.Id
+			}
+		}
+
+		// a node which ignores the id returns the same page again
+		if opt.Id != "" && last == opt.Id {
+			return txs, nil
+		}
+
+		txs = append(txs, page...)
+
+		if len(page) < pageSize || last == "" {
+			return txs, nil
+		}
+
+		opt = &BlockTransactionsOption{PageSize: pageSize, Id: last}
+	}
+}
+
 // GetBlocksByHeightWithLimit Returns blocks information for a given block height and limit
 func (b *BlockchainService) GetBlocksByHeightWithLimit(ctx context.Context, height, limit *big.Int) ([]*BlockInfo, error) {
 	if height == nil || height.Int64() == 0 {
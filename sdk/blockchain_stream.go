@@ -0,0 +1,349 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"golang.org/x/net/context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStreamPageSize    = 100
+	defaultStreamConcurrency = 4
+)
+
+// StreamOptions configures BlockchainService.StreamBlocks; zero values mean
+// defaults.
+type StreamOptions struct {
+	// ToHeight is the last block streamed. When nil the stream follows the
+	// chain once it reaches the chain height, until the context is done.
+	ToHeight *big.Int
+	// Websocket is subscribed to new blocks once the stream reaches the chain
+	// height. When nil the chain height is polled every PollInterval.
+	Websocket *ClientWebsocket
+	// PollInterval between two chain height requests, one second by default.
+	PollInterval time.Duration
+	// PageSize is the number of blocks requested at once with
+	// GetBlocksByHeightWithLimit, 100 by default. It is also the number of
+	// blocks fetched ahead of the consumer.
+	PageSize int64
+	// Concurrency is the number of block transactions requested at once, 4
+	// by default.
+	Concurrency int
+}
+
+// StreamedBlock is a block along with its transactions.
+type StreamedBlock struct {
+	*BlockInfo
+	Transactions []Transaction
+}
+
+// BlockStream delivers blocks in order of height, without gaps or
+// duplicates.
+type BlockStream struct {
+	// Blocks is closed when the stream ends; Err tells why.
+	Blocks <-chan *StreamedBlock
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Err returns the error which ended the stream, nil when it reached
+// StreamOptions.ToHeight. It should be called once Blocks is closed.
+func (s *BlockStream) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Close ends the stream and waits until Blocks is closed.
+func (s *BlockStream) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// StreamBlocks streams the blocks from fromHeight on, along with their
+// transactions. Past blocks are requested a page at a time, with their
+// transactions requested concurrently, ahead of the consumer. Once the chain
+// height is reached, new blocks are taken from StreamOptions.Websocket, or
+// polled, and any block the subscription skipped is requested as well.
+// A block the node returns fewer transactions of than its NumTransactions
+// ends the stream with ErrMissingBlockTransactions.
+func (b *BlockchainService) StreamBlocks(ctx context.Context, fromHeight *big.Int, opts *StreamOptions) (*BlockStream, error) {
+	if fromHeight == nil || fromHeight.Sign() <= 0 {
+		return nil, ErrNilOrZeroHeight
+	}
+
+	o := StreamOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.ToHeight != nil && o.ToHeight.Cmp(fromHeight) < 0 {
+		return nil, ErrInvalidHeightRange
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = defaultStreamPageSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultStreamConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	blocks := make(chan *StreamedBlock, o.PageSize)
+
+	s := &BlockStream{
+		Blocks: blocks,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	st := &blockStreamer{
+		service: b,
+		opts:    o,
+		next:    new(big.Int).Set(fromHeight),
+		blocks:  blocks,
+	}
+
+	go func() {
+		defer close(s.done)
+		defer close(blocks)
+		defer cancel()
+
+		s.err = st.run(ctx)
+	}()
+
+	return s, nil
+}
+
+type blockStreamer struct {
+	service *BlockchainService
+	opts    StreamOptions
+	// next is the height of the next block to deliver
+	next   *big.Int
+	blocks chan<- *StreamedBlock
+}
+
+func (st *blockStreamer) run(ctx context.Context) error {
+	height, err := st.service.GetBlockchainHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := st.catchUp(ctx, height); err != nil {
+		return err
+	}
+
+	if st.done() {
+		return nil
+	}
+
+	if st.opts.Websocket != nil {
+		return st.subscribe(ctx)
+	}
+
+	return st.poll(ctx)
+}
+
+// done tells if the last block of the stream was delivered.
+func (st *blockStreamer) done() bool {
+	return st.opts.ToHeight != nil && st.next.Cmp(st.opts.ToHeight) > 0
+}
+
+// poll catches up with the chain height every PollInterval.
+func (st *blockStreamer) poll(ctx context.Context) error {
+	ticker := time.NewTicker(st.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			height, err := st.service.GetBlockchainHeight(ctx)
+			if err != nil {
+				return err
+			}
+
+			if err := st.catchUp(ctx, height); err != nil {
+				return err
+			}
+
+			if st.done() {
+				return nil
+			}
+		}
+	}
+}
+
+// subscribe delivers the blocks of the websocket subscription. Blocks already
+// delivered are dropped and the blocks between the last one delivered and a
+// new one are requested first.
+func (st *blockStreamer) subscribe(ctx context.Context) error {
+	sub, err := st.opts.Websocket.Subscribe.Block()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case block, ok := <-sub.Ch:
+			if !ok {
+				return nil
+			}
+
+			if block == nil || block.Height == nil || block.Height.Cmp(st.next) < 0 {
+				continue
+			}
+
+			if err := st.catchUp(ctx, new(big.Int).Sub(block.Height, big.NewInt(1))); err != nil {
+				return err
+			}
+
+			if st.done() {
+				return nil
+			}
+
+			// the number of transactions is not part of websocket blocks
+			txs, err := st.service.GetAllBlockTransactions(ctx, block.Height, 0)
+			if err != nil {
+				return err
+			}
+
+			if err := st.deliver(ctx, &StreamedBlock{block, txs}); err != nil {
+				return err
+			}
+
+			if st.done() {
+				return nil
+			}
+		}
+	}
+}
+
+// catchUp delivers the blocks from next up to height, or ToHeight when it is
+// lower, a page at a time.
+func (st *blockStreamer) catchUp(ctx context.Context, height *big.Int) error {
+	to := height
+	if st.opts.ToHeight != nil && st.opts.ToHeight.Cmp(to) < 0 {
+		to = st.opts.ToHeight
+	}
+
+	for st.next.Cmp(to) <= 0 {
+		n := new(big.Int).Sub(to, st.next).Int64() + 1
+		if n > st.opts.PageSize {
+			n = st.opts.PageSize
+		}
+
+		page, err := st.page(ctx, st.next, n)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range page {
+			if err := st.deliver(ctx, block); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// page returns the n blocks from height on, with their transactions. Blocks
+// missing from the GetBlocksByHeightWithLimit response are requested one by
+// one.
+func (st *blockStreamer) page(ctx context.Context, height *big.Int, n int64) ([]*StreamedBlock, error) {
+	infos, err := st.service.GetBlocksByHeightWithLimit(ctx, height, big.NewInt(n))
+	if err != nil {
+		return nil, err
+	}
+
+	byHeight := make(map[string]*BlockInfo, len(infos))
+	for _, info := range infos {
+		if info != nil && info.Height != nil {
+			byHeight[info.Height.String()] = info
+		}
+	}
+
+	var (
+		page = make([]*StreamedBlock, n)
+		errs = make([]error, n)
+		sem  = make(chan struct{}, st.opts.Concurrency)
+		wg   sync.WaitGroup
+	)
+
+	for i := int64(0); i < n; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			h := new(big.Int).Add(height, big.NewInt(i))
+
+			info, ok := byHeight[h.String()]
+			if !ok {
+				if info, errs[i] = st.service.GetBlockByHeight(ctx, h); errs[i] != nil {
+					return
+				}
+			}
+
+			var txs []Transaction
+			if txs, errs[i] = st.transactions(ctx, info); errs[i] == nil {
+				page[i] = &StreamedBlock{info, txs}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// transactions returns all the transactions of the block, without a request
+// when the REST API reports it has none.
+func (st *blockStreamer) transactions(ctx context.Context, block *BlockInfo) ([]Transaction, error) {
+	if block.NumTransactions == 0 {
+		return []Transaction{}, nil
+	}
+
+	txs, err := st.service.GetAllBlockTransactions(ctx, block.Height, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(txs)) < block.NumTransactions {
+		return nil, ErrMissingBlockTransactions
+	}
+
+	return txs, nil
+}
+
+func (st *blockStreamer) deliver(ctx context.Context, block *StreamedBlock) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case st.blocks <- block:
+		st.next.Add(st.next, big.NewInt(1))
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// streamTestBlock returns block height, whose hash is its height and whose
+// numTransactions is numTxs.
+func streamTestBlock(height, numTxs int) string {
+	return testBlockJSON(height, fmt.Sprintf("%064X", height), fmt.Sprintf("%064X", height-1), numTxs)
+}
+
+// addStreamTestTransaction serves a transaction for block height.
+func addStreamTestTransaction(t *testing.T, m *sdkMock, height int) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockGetTransactionRoute, height),
+		RespBody: "[" + testTransferJSON(t, height, testNEMPublicKey, account.Address, "", &Mosaic{XemMosaicId, big.NewInt(1)}) + "]",
+	})
+}
+
+// newStreamTestMock serves the chain height and the pages of blocks, keyed by
+// the first height and the limit the stream requests them with; odd blocks
+// have a transaction.
+func newStreamTestMock(t *testing.T, height int, pages map[[2]int][]int) *sdkMock {
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     blockHeightRoute,
+		RespBody: fmt.Sprintf(`{"height": [%d, 0]}`, height),
+	})
+
+	for page, heights := range pages {
+		blocks := make([]string, len(heights))
+		for i, h := range heights {
+			blocks[i] = streamTestBlock(h, h%2)
+
+			if h%2 == 1 {
+				addStreamTestTransaction(t, m, h)
+			}
+		}
+
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(blockInfoRoute, page[0], page[1]),
+			RespBody: "[" + strings.Join(blocks, ",") + "]",
+		})
+	}
+
+	return m
+}
+
+// receiveBlocks reads n blocks of the stream, checking their heights follow
+// from.
+func receiveBlocks(t *testing.T, stream *BlockStream, from, n int) {
+	for i := from; i < from+n; i++ {
+		select {
+		case block, ok := <-stream.Blocks:
+			if !ok {
+				t.Errorf("stream ended before block %d: %v", i, stream.Err())
+				return
+			}
+			assert.Equal(t, int64(i), block.Height.Int64())
+			assert.Len(t, block.Transactions, i%2)
+		case <-time.After(wsWait):
+			t.Fatalf("timed out waiting for block %d", i)
+		}
+	}
+}
+
+func TestBlockchainService_StreamBlocks(t *testing.T) {
+	// the page of blocks 1 to 4 leaves out block 2, which is then requested
+	// on its own; the transactions of blocks without any are not served
+	m := newStreamTestMock(t, 7, map[[2]int][]int{{1, 4}: {1, 3, 4}, {5, 2}: {5, 6}})
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockByHeightRoute, 2),
+		RespBody: streamTestBlock(2, 0),
+	})
+
+	client := m.getTestNetClientUnsafe()
+
+	stream, err := client.Blockchain.StreamBlocks(ctx, big.NewInt(1), &StreamOptions{ToHeight: big.NewInt(6), PageSize: 4, Concurrency: 2})
+	assert.Nilf(t, err, "BlockchainService.StreamBlocks returned error: %s", err)
+
+	receiveBlocks(t, stream, 1, 6)
+
+	_, ok := <-stream.Blocks
+	assert.False(t, ok)
+	assert.Nil(t, stream.Err())
+
+	_, err = client.Blockchain.StreamBlocks(ctx, big.NewInt(3), &StreamOptions{ToHeight: big.NewInt(2)})
+	assert.Equal(t, ErrInvalidHeightRange, err)
+}
+
+func TestBlockchainService_StreamBlocks_MissingTransactions(t *testing.T) {
+	// block 1 reports two transactions, but the node returns one
+	m := newStreamTestMock(t, 1, nil)
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockInfoRoute, 1, 1),
+		RespBody: "[" + streamTestBlock(1, 2) + "]",
+	})
+	addStreamTestTransaction(t, m, 1)
+
+	stream, err := m.getTestNetClientUnsafe().Blockchain.StreamBlocks(ctx, big.NewInt(1), &StreamOptions{ToHeight: big.NewInt(1)})
+	assert.Nil(t, err)
+
+	_, ok := <-stream.Blocks
+	assert.False(t, ok)
+	assert.Equal(t, ErrMissingBlockTransactions, stream.Err())
+}
+
+// streamTestTransport serves the chain height, which grows during the test,
+// and leaves the other requests to the mock, whose routes are static.
+type streamTestTransport struct {
+	mu     sync.Mutex
+	height int
+}
+
+func (tr *streamTestTransport) setHeight(height int) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.height = height
+}
+
+func (tr *streamTestTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Path != blockHeightRoute {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(fmt.Sprintf(`{"height": [%d, 0]}`, tr.height))),
+		Request:    r,
+	}, nil
+}
+
+func TestBlockchainService_StreamBlocks_Poll(t *testing.T) {
+	m := newStreamTestMock(t, 2, map[[2]int][]int{{1, 2}: {1, 2}, {3, 3}: {3, 4, 5}})
+	defer m.Close()
+
+	tr := &streamTestTransport{height: 2}
+	conf, err := NewConfig(m.GetServerURL(), TestNet)
+	assert.Nil(t, err)
+	client := NewClient(&http.Client{Transport: tr}, conf)
+
+	stream, err := client.Blockchain.StreamBlocks(ctx, big.NewInt(1), &StreamOptions{PollInterval: 10 * time.Millisecond})
+	assert.Nil(t, err)
+	defer stream.Close()
+
+	receiveBlocks(t, stream, 1, 2)
+	tr.setHeight(5)
+	receiveBlocks(t, stream, 3, 3)
+
+	assert.Nil(t, stream.Close())
+	_, ok := <-stream.Blocks
+	assert.False(t, ok)
+}
+
+func TestBlockchainService_StreamBlocks_Websocket(t *testing.T) {
+	// blocks 4 and 5 are skipped by the subscription and requested as a page
+	m := newStreamTestMock(t, 2, map[[2]int][]int{{1, 2}: {1, 2}, {4, 2}: {4, 5}})
+	defer m.Close()
+
+	// websocket blocks have no numTransactions, so theirs are always requested
+	addStreamTestTransaction(t, m, 3)
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockGetTransactionRoute, 6),
+		RespBody: "[]",
+	})
+
+	wsSrv, ws := newWsTestClient(t)
+	defer wsSrv.Close()
+
+	stream, err := m.getTestNetClientUnsafe().Blockchain.StreamBlocks(ctx, big.NewInt(1), &StreamOptions{ToHeight: big.NewInt(6), Websocket: ws})
+	assert.Nil(t, err)
+	defer stream.Close()
+
+	receiveBlocks(t, stream, 1, 2)
+	assert.Nil(t, wsSrv.WaitSubscribed(pathBlock, wsWait))
+
+	// block 2 was delivered already
+	for _, height := range []int{2, 3, 6} {
+		_, err := wsSrv.PublishBlock([]byte(streamTestBlock(height, 0)))
+		assert.Nil(t, err)
+	}
+
+	receiveBlocks(t, stream, 3, 4)
+
+	_, ok := <-stream.Blocks
+	assert.False(t, ok)
+	assert.Nil(t, stream.Err())
+}
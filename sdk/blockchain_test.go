@@ -10,6 +10,10 @@ import (
 	"github.com/proximax-storage/proximax-utils-go/tests"
 	"github.com/stretchr/testify/assert"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -227,3 +231,69 @@ func TestBlockchainService_GetBlockTransactions(t *testing.T) {
 		assert.Equal(t, wantBlockTransactions[key].GetAbstractTransaction().Signature, transaction.GetAbstractTransaction().Signature)
 	}
 }
+
+func TestBlockchainService_GetAllBlockTransactions(t *testing.T) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+
+	// 5 transfers, whose ids are derived from their position
+	txs := make([]string, 5)
+	for i := range txs {
+		txs[i] = testTransferJSON(t, i+1, testNEMPublicKey, account.Address, "", &Mosaic{XemMosaicId, big.NewInt(1)})
+	}
+
+	// sdkMock routes don't tell requests apart by their query, so the pages
+	// after the transaction of id are served here
+	requests := make([]string, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fmt.Sprintf(blockGetTransactionRoute, 5) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests = append(requests, r.URL.RawQuery)
+
+		start := 0
+		if id := r.URL.Query().Get("id"); id != "" {
+			n, _ := strconv.ParseInt(id, 16, 64)
+			start = int(n)
+		}
+		end, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		end += start
+		if end > len(txs) {
+			end = len(txs)
+		}
+
+		fmt.Fprintf(w, "[%s]", strings.Join(txs[start:end], ","))
+	}))
+	defer srv.Close()
+
+	client := setupWithAddress(srv.URL)
+
+	got, err := client.Blockchain.GetAllBlockTransactions(ctx, big.NewInt(5), 2)
+	assert.Nilf(t, err, "BlockchainService.GetAllBlockTransactions returned error: %s", err)
+	assert.Len(t, got, 5)
+	for i, tx := range got {
+		assert.Equal(t, fmt.Sprintf("%024X", i+1), tx.GetAbstractTransaction().TransactionInfo.Id)
+	}
+	assert.Equal(t, []string{
+		"pageSize=2",
+		fmt.Sprintf("id=%024X&pageSize=2", 2),
+		fmt.Sprintf("id=%024X&pageSize=2", 4),
+	}, requests)
+
+	_, err = client.Blockchain.GetAllBlockTransactions(ctx, nil, 2)
+	assert.Equal(t, ErrNilOrZeroHeight, err)
+}
+
+func TestBlockchainService_GetAllBlockTransactionsSamePage(t *testing.T) {
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockGetTransactionRoute, 7),
+		RespBody: blockTransactionsJSON,
+	})
+	defer m.Close()
+
+	// the node ignores the id and serves the full page again
+	got, err := m.getTestNetClientUnsafe().Blockchain.GetAllBlockTransactions(ctx, big.NewInt(7), len(wantBlockTransactions))
+	assert.Nilf(t, err, "BlockchainService.GetAllBlockTransactions returned error: %s", err)
+	assert.Len(t, got, len(wantBlockTransactions))
+}
@@ -0,0 +1,340 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"container/list"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetadataInfoTTL = time.Minute
+	defaultMetadataNameTTL = time.Hour
+	defaultMetadataSize    = 10000
+)
+
+// MetadataCacheConfig bounds a MetadataCache; zero values mean defaults.
+type MetadataCacheConfig struct {
+	// InfoTTL is how long a MosaicInfo or NamespaceInfo is cached, 1 minute
+	// by default. Their supply and expiry change on chain.
+	InfoTTL time.Duration
+	// NameTTL is how long a MosaicName or NamespaceName is cached, 1 hour by
+	// default.
+	NameTTL time.Duration
+	// Size is the maximum number of cached entries, 10000 by default. The
+	// least recently used entries are evicted first.
+	Size int
+}
+
+// MetadataCache caches mosaic and namespace infos and names. It can be
+// shared by several clients of the same network through Config.Cache.
+//
+// Concurrent GetMosaic and GetNamespace calls for the same id share one
+// request, which goes on as long as one of them waits for it. The cached
+// values are shared too and must not be modified.
+type MetadataCache struct {
+	conf MetadataCacheConfig
+	now  func() time.Time
+
+	mu    sync.Mutex
+	items map[metadataKey]*list.Element
+	lru   *list.List
+	calls map[metadataKey]*metadataCall
+	// gen is bumped on invalidation, so that requests started before it
+	// don't cache stale values.
+	gen uint64
+}
+
+type metadataKind uint8
+
+const (
+	mosaicInfoKind metadataKind = iota
+	namespaceInfoKind
+	mosaicNameKind
+	namespaceNameKind
+)
+
+type metadataKey struct {
+	kind metadataKind
+	id   string
+}
+
+type metadataEntry struct {
+	key     metadataKey
+	value   interface{}
+	expires time.Time
+}
+
+type metadataCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+	// waiters is the number of callers waiting for the call, which is
+	// canceled when all of them gave up
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewMetadataCache returns an empty cache; conf may be nil.
+func NewMetadataCache(conf *MetadataCacheConfig) *MetadataCache {
+	c := &MetadataCache{
+		now:   time.Now,
+		items: make(map[metadataKey]*list.Element),
+		lru:   list.New(),
+		calls: make(map[metadataKey]*metadataCall),
+	}
+
+	if conf != nil {
+		c.conf = *conf
+	}
+	if c.conf.InfoTTL <= 0 {
+		c.conf.InfoTTL = defaultMetadataInfoTTL
+	}
+	if c.conf.NameTTL <= 0 {
+		c.conf.NameTTL = defaultMetadataNameTTL
+	}
+	if c.conf.Size <= 0 {
+		c.conf.Size = defaultMetadataSize
+	}
+
+	return c
+}
+
+// Len returns the number of cached entries, including expired ones not
+// evicted yet.
+func (c *MetadataCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Purge removes all entries.
+func (c *MetadataCache) Purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[metadataKey]*list.Element)
+	c.lru.Init()
+	c.gen++
+}
+
+// Invalidate removes the entries changed by tx: the mosaic of a
+// MosaicDefinition or MosaicSupplyChange transaction, and the namespace of a
+// RegisterNamespace transaction along with the infos of its sub-namespaces
+// and mosaics. Aggregate transactions are looked into.
+func (c *MetadataCache) Invalidate(tx Transaction) {
+	if c == nil || tx == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(tx)
+	c.gen++
+}
+
+func (c *MetadataCache) invalidate(tx Transaction) {
+	switch tx := tx.(type) {
+	case *AggregateTransaction:
+		for _, inner := range tx.InnerTransactions {
+			c.invalidate(inner)
+		}
+	case *MosaicDefinitionTransaction:
+		if tx.MosaicId != nil {
+			c.remove(metadataKey{mosaicInfoKind, tx.MosaicId.String()})
+			c.remove(metadataKey{mosaicNameKind, tx.MosaicId.String()})
+		}
+	case *MosaicSupplyChangeTransaction:
+		if tx.MosaicId != nil {
+			c.remove(metadataKey{mosaicInfoKind, tx.MosaicId.String()})
+		}
+	case *RegisterNamespaceTransaction:
+		if tx.NamespaceId != nil {
+			c.removeNamespace(tx.NamespaceId.String())
+		}
+	}
+}
+
+// removeNamespace removes the namespace and the infos which embed it in
+// their hierarchy.
+func (c *MetadataCache) removeNamespace(id string) {
+	c.remove(metadataKey{namespaceInfoKind, id})
+	c.remove(metadataKey{namespaceNameKind, id})
+
+	for key, el := range c.items {
+		var nsInfo *NamespaceInfo
+
+		switch v := el.Value.(*metadataEntry).value.(type) {
+		case *NamespaceInfo:
+			nsInfo = v.Parent
+		case *MosaicInfo:
+			nsInfo = v.Namespace
+		}
+
+		for ; nsInfo != nil; nsInfo = nsInfo.Parent {
+			if nsInfo.NamespaceId != nil && nsInfo.NamespaceId.String() == id {
+				c.remove(key)
+				break
+			}
+		}
+	}
+}
+
+// Watch invalidates the cache with the transactions received, e.g. from
+// ClientWebsocket.Subscribe.ConfirmedAdded, until the context is done or the
+// channel is closed.
+func (c *MetadataCache) Watch(ctx context.Context, txs <-chan Transaction) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case tx, ok := <-txs:
+			if !ok {
+				return nil
+			}
+
+			c.Invalidate(tx)
+		}
+	}
+}
+
+func (c *MetadataCache) get(key metadataKey) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*metadataEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(key)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *MetadataCache) set(key metadataKey, value interface{}) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(key, value)
+}
+
+// add caches value, evicting the least recently used entries over the size.
+func (c *MetadataCache) add(key metadataKey, value interface{}) {
+	ttl := c.conf.NameTTL
+	if key.kind == mosaicInfoKind || key.kind == namespaceInfoKind {
+		ttl = c.conf.InfoTTL
+	}
+
+	entry := &metadataEntry{key, value, c.now().Add(ttl)}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.conf.Size {
+		c.remove(c.lru.Back().Value.(*metadataEntry).key)
+	}
+}
+
+func (c *MetadataCache) remove(key metadataKey) {
+	if el, ok := c.items[key]; ok {
+		c.lru.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// do returns the cached value of key or the value of fn, which concurrent
+// callers of the same key share. fn runs with a context of its own, so a
+// caller whose context is done stops waiting without failing the others.
+// Without a cache it just calls fn.
+func (c *MetadataCache) do(ctx context.Context, key metadataKey, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return fn(ctx)
+	}
+
+	if v, ok := c.get(key); ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.Background())
+		call = &metadataCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+		go c.call(callCtx, key, call, c.gen, fn)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// the next caller starts a new call instead of joining this one
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+			call.cancel()
+		}
+		c.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// call runs fn for the waiters of call and caches its value, unless the cache
+// was invalidated since gen.
+func (c *MetadataCache) call(ctx context.Context, key metadataKey, call *metadataCall, gen uint64, fn func(ctx context.Context) (interface{}, error)) {
+	value, err := fn(ctx)
+
+	c.mu.Lock()
+	call.value, call.err = value, err
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	if err == nil && gen == c.gen {
+		c.add(key, value)
+	}
+	c.mu.Unlock()
+
+	call.cancel()
+	close(call.done)
+}
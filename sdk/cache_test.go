@@ -0,0 +1,346 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cacheTestTransport counts the requests of a client and delays the ones to
+// the delayed path, so that concurrent callers pile up.
+type cacheTestTransport struct {
+	delayed string
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (tr *cacheTestTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tr.mu.Lock()
+	tr.requests[r.Method+" "+r.URL.Path]++
+	tr.mu.Unlock()
+
+	if r.URL.Path == tr.delayed {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// counted returns the requests counted since the previous call.
+func (tr *cacheTestTransport) counted() map[string]int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	c := tr.requests
+	tr.requests = make(map[string]int)
+	return c
+}
+
+// cacheTestBlockingTransport holds every request until its context is done
+// and reports the context error.
+type cacheTestBlockingTransport struct {
+	started  chan struct{}
+	canceled chan error
+}
+
+func (tr *cacheTestBlockingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tr.started <- struct{}{}
+
+	<-r.Context().Done()
+	tr.canceled <- r.Context().Err()
+
+	return nil, r.Context().Err()
+}
+
+func newCacheTestClient(t *testing.T, m *sdkMock, tr *cacheTestTransport) *Client {
+	conf, err := NewConfig(m.GetServerURL(), TestNet)
+	assert.Nil(t, err)
+	conf.Cache = NewMetadataCache(nil)
+
+	tr.requests = make(map[string]int)
+
+	return NewClient(&http.Client{Transport: tr}, conf)
+}
+
+func TestMetadataCache_Bounds(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	c := NewMetadataCache(&MetadataCacheConfig{InfoTTL: time.Minute, Size: 2})
+	c.now = func() time.Time { return now }
+
+	c.set(metadataKey{mosaicInfoKind, "1"}, 1)
+	c.set(metadataKey{mosaicInfoKind, "2"}, 2)
+
+	// 1 becomes the most recently used, so 2 is evicted
+	_, ok := c.get(metadataKey{mosaicInfoKind, "1"})
+	assert.True(t, ok)
+	c.set(metadataKey{mosaicNameKind, "3"}, 3)
+
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.get(metadataKey{mosaicInfoKind, "2"})
+	assert.False(t, ok)
+
+	// infos expire before names
+	now = now.Add(time.Minute)
+	_, ok = c.get(metadataKey{mosaicInfoKind, "1"})
+	assert.False(t, ok)
+	v, ok := c.get(metadataKey{mosaicNameKind, "3"})
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+
+	var nilCache *MetadataCache
+	nilCache.Invalidate(&MosaicSupplyChangeTransaction{MosaicId: testMosaicId})
+	_, ok = nilCache.get(metadataKey{mosaicInfoKind, "1"})
+	assert.False(t, ok)
+}
+
+func TestMetadataCache_do(t *testing.T) {
+	c := NewMetadataCache(nil)
+	key := metadataKey{mosaicInfoKind, "1"}
+
+	// waiters returns the number of callers waiting for the call of key
+	waiters := func() int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if call, ok := c.calls[key]; ok {
+			return call.waiters
+		}
+		return 0
+	}
+
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// the caller which starts the call gives up, the other one gets the value
+	leaderCtx, cancel := context.WithCancel(ctx)
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.do(leaderCtx, key, fn)
+		leaderErr <- err
+	}()
+
+	value := make(chan interface{}, 1)
+	go func() {
+		for waiters() != 1 {
+			time.Sleep(time.Millisecond)
+		}
+
+		v, err := c.do(ctx, key, fn)
+		assert.Nil(t, err)
+		value <- v
+	}()
+
+	for waiters() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-leaderErr)
+
+	close(release)
+	assert.Equal(t, 1, <-value)
+
+	v, ok := c.get(key)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// the call is canceled once all callers gave up
+	key = metadataKey{mosaicInfoKind, "2"}
+	canceled := make(chan error, 1)
+
+	callerCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		for waiters() != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	_, err := c.do(callerCtx, key, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	select {
+	case err := <-canceled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Error("the call was not canceled")
+	}
+
+	_, ok = c.get(key)
+	assert.False(t, ok)
+}
+
+func TestMetadataCache_Services(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(mosaicRoute, testMosaicPathID),
+		RespBody: tplMosaic,
+	})
+	m.AddRouter(&mock.Router{
+		Path:     mosaicsRoute,
+		RespBody: "[" + tplMosaic + "]",
+	})
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(namespaceRoute, mosaicNamespace),
+		RespBody: tplInfo,
+	})
+
+	tr := &cacheTestTransport{delayed: fmt.Sprintf(mosaicRoute, testMosaicPathID)}
+	client := newCacheTestClient(t, m, tr)
+	cache := client.config.Cache
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			mscInfo, err := client.Mosaic.GetMosaic(ctx, testMosaicId)
+			assert.Nil(t, err)
+			assert.Equal(t, "84b3552d375ffa4b", mscInfo.Namespace.NamespaceId.toHexString())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{
+		"GET " + fmt.Sprintf(mosaicRoute, testMosaicPathID):   1,
+		"GET " + fmt.Sprintf(namespaceRoute, mosaicNamespace): 1,
+	}, tr.counted())
+
+	mscInfos, err := client.Mosaic.GetMosaics(ctx, []*MosaicId{testMosaicId})
+	assert.Nil(t, err)
+	assert.Len(t, mscInfos, 1)
+	assert.Len(t, tr.counted(), 0)
+
+	// the namespace is embedded in the mosaic info, so both are dropped
+	cache.Invalidate(&AggregateTransaction{InnerTransactions: []Transaction{
+		&RegisterNamespaceTransaction{NamespaceId: mscInfos[0].Namespace.NamespaceId},
+	}})
+
+	mscInfos, err = client.Mosaic.GetMosaics(ctx, []*MosaicId{testMosaicId})
+	assert.Nil(t, err)
+	assert.Len(t, mscInfos, 1)
+	assert.Equal(t, map[string]int{
+		"POST " + mosaicsRoute:                                1,
+		"GET " + fmt.Sprintf(namespaceRoute, mosaicNamespace): 1,
+	}, tr.counted())
+
+	txs := make(chan Transaction, 1)
+	txs <- &MosaicSupplyChangeTransaction{MosaicId: testMosaicId}
+	close(txs)
+	assert.Nil(t, cache.Watch(ctx, txs))
+
+	_, err = client.Mosaic.GetMosaic(ctx, testMosaicId)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{
+		"GET " + fmt.Sprintf(mosaicRoute, testMosaicPathID): 1,
+	}, tr.counted())
+}
+
+func TestMetadataCache_CanceledRequest(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	conf, err := NewConfig(m.GetServerURL(), TestNet)
+	assert.Nil(t, err)
+	conf.Cache = NewMetadataCache(nil)
+
+	tr := &cacheTestBlockingTransport{started: make(chan struct{}, 1), canceled: make(chan error, 1)}
+	client := NewClient(&http.Client{Transport: tr}, conf)
+
+	callerCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-tr.started
+		cancel()
+	}()
+
+	// the only caller gives up, so the shared request is aborted
+	_, err = client.Mosaic.GetMosaic(callerCtx, testMosaicId)
+	assert.Equal(t, context.Canceled, err)
+
+	select {
+	case err := <-tr.canceled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Error("the request was not aborted")
+	}
+}
+
+// TestMetadataCache_ParallelGetNamespace is meant to be run with -race: the
+// sub-namespaces of a cached namespace are built while it is read.
+func TestMetadataCache_ParallelGetNamespace(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	// foo (1), foo.bar (2) and the sub-namespaces 3 to 10 of foo.bar
+	parents := map[uint64]uint64{1: 0, 2: 1}
+	for id := uint64(3); id <= 10; id++ {
+		parents[id] = 2
+	}
+	for id, parent := range parents {
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(namespaceRoute, BigIntegerToHex(new(big.Int).SetUint64(id))),
+			RespBody: testNamespaceJSON(id, parent, "[6000, 0]"),
+		})
+	}
+
+	client := newCacheTestClient(t, m, &cacheTestTransport{})
+
+	nsId := func(id uint64) *NamespaceId {
+		return bigIntToNamespaceId(new(big.Int).SetUint64(id))
+	}
+
+	bar, err := client.Namespace.GetNamespace(ctx, nsId(2))
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for id := uint64(3); id <= 10; id++ {
+		wg.Add(2)
+
+		go func(id uint64) {
+			defer wg.Done()
+
+			nsInfo, err := client.Namespace.GetNamespace(ctx, nsId(id))
+			assert.Nil(t, err)
+			assert.True(t, nsInfo.Parent == bar)
+		}(id)
+
+		go func() {
+			defer wg.Done()
+
+			nsInfo, err := client.Namespace.GetNamespace(ctx, nsId(2))
+			assert.Nil(t, err)
+			assert.Equal(t, big.NewInt(1), namespaceIdToBigInt(nsInfo.Parent.NamespaceId))
+			assert.Nil(t, nsInfo.Parent.Parent)
+		}()
+	}
+	wg.Wait()
+}
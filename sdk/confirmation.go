@@ -0,0 +1,234 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// ConfirmationOptions configures a ConfirmationWatcher
+type ConfirmationOptions struct {
+	// Depth is the number of confirmations after which OnConfirmed is called, 1 by default
+	Depth uint64
+	// OnDepth is called for every new block while the transaction is confirmed
+	OnDepth func(status *ConfirmationStatus)
+	// OnConfirmed is called once the transaction reaches Depth confirmations
+	OnConfirmed func(status *ConfirmationStatus)
+	// OnRollback is called when a confirmed transaction is no longer in the block it was confirmed in
+	OnRollback func(status *ConfirmationStatus)
+}
+
+// ConfirmationStatus describes the confirmation depth of a transaction
+type ConfirmationStatus struct {
+	Hash        Hash
+	Height      *big.Int
+	BlockHash   string
+	Depth       uint64
+	Transaction Transaction
+}
+
+// ConfirmationWatcher reports the confirmation depth of a transaction as new blocks arrive
+type ConfirmationWatcher struct {
+	client *Client
+	hash   Hash
+	opts   ConfirmationOptions
+	status *ConfirmationStatus
+	fired  bool
+}
+
+// NewConfirmationWatcher returns a watcher of transaction with the given hash
+func NewConfirmationWatcher(client *Client, hash Hash, opts *ConfirmationOptions) (*ConfirmationWatcher, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	if len(hash) == 0 {
+		return nil, ErrBlankHash
+	}
+
+	w := &ConfirmationWatcher{client: client, hash: hash}
+
+	if opts != nil {
+		w.opts = *opts
+	}
+
+	if w.opts.Depth == 0 {
+		w.opts.Depth = 1
+	}
+
+	return w, nil
+}
+
+// WatchWebsocket subscribes to new blocks and watches them until the context is done
+func (w *ConfirmationWatcher) WatchWebsocket(ctx context.Context, ws *ClientWebsocket) error {
+	sub, err := ws.Subscribe.Block()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	return w.Watch(ctx, sub.Ch)
+}
+
+// Watch processes blocks until the context is done or the channel is closed
+func (w *ConfirmationWatcher) Watch(ctx context.Context, blocks <-chan *BlockInfo) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case block, ok := <-blocks:
+			if !ok {
+				return nil
+			}
+
+			if err := w.OnBlock(ctx, block); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// OnBlock updates the confirmation depth of the transaction with a new block
+func (w *ConfirmationWatcher) OnBlock(ctx context.Context, block *BlockInfo) error {
+	if block == nil || block.Height == nil {
+		return nil
+	}
+
+	if w.status != nil {
+		rolledBack, err := w.isRolledBack(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rolledBack {
+			status := w.status
+			w.status, w.fired = nil, false
+
+			if w.opts.OnRollback != nil {
+				w.opts.OnRollback(status)
+			}
+		}
+	}
+
+	if w.status == nil {
+		status, err := w.findConfirmation(ctx)
+		if err != nil {
+			return err
+		}
+
+		if status == nil {
+			return nil
+		}
+
+		w.status = status
+	}
+
+	if block.Height.Cmp(w.status.Height) < 0 {
+		return nil
+	}
+
+	w.status.Depth = new(big.Int).Sub(block.Height, w.status.Height).Uint64() + 1
+
+	if w.opts.OnDepth != nil {
+		w.opts.OnDepth(w.status)
+	}
+
+	if !w.fired && w.status.Depth >= w.opts.Depth {
+		w.fired = true
+
+		if w.opts.OnConfirmed != nil {
+			w.opts.OnConfirmed(w.status)
+		}
+	}
+
+	return nil
+}
+
+// findConfirmation returns the block in which the transaction is confirmed or nil when it is not confirmed yet
+func (w *ConfirmationWatcher) findConfirmation(ctx context.Context) (*ConfirmationStatus, error) {
+	tx, err := w.getTransaction(ctx)
+	if err == ErrResourceNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !tx.GetAbstractTransaction().IsConfirmed() {
+		return nil, nil
+	}
+
+	height := tx.GetAbstractTransaction().Height
+
+	block, err := w.client.Blockchain.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfirmationStatus{
+		Hash:        w.hash,
+		Height:      height,
+		BlockHash:   block.Hash,
+		Transaction: tx,
+	}, nil
+}
+
+// isRolledBack checks that the transaction is still at the same height and the block at that height was not replaced
+func (w *ConfirmationWatcher) isRolledBack(ctx context.Context) (bool, error) {
+	tx, err := w.getTransaction(ctx)
+	if err == ErrResourceNotFound {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	atx := tx.GetAbstractTransaction()
+	if !atx.IsConfirmed() || atx.Height.Cmp(w.status.Height) != 0 {
+		return true, nil
+	}
+
+	block, err := w.getBlock(ctx, w.status.Height)
+	if err == ErrResourceNotFound {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return block.Hash != w.status.BlockHash, nil
+}
+
+// getTransaction returns the transaction, or ErrResourceNotFound while the node does not know it
+func (w *ConfirmationWatcher) getTransaction(ctx context.Context) (Transaction, error) {
+	var b bytes.Buffer
+
+	_, err := w.client.doNewRequestOrNotFound(ctx, http.MethodGet, fmt.Sprintf(transactionRoute, w.hash), nil, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapTransaction(&b)
+}
+
+// getBlock returns the block at height, or ErrResourceNotFound when the chain is no longer that high
+func (w *ConfirmationWatcher) getBlock(ctx context.Context, height *big.Int) (*BlockInfo, error) {
+	dto := &blockInfoDTO{}
+
+	_, err := w.client.doNewRequestOrNotFound(ctx, http.MethodGet, fmt.Sprintf(blockByHeightRoute, height), nil, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.toStruct()
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const confirmationTxHash = "45AC1259DABD7163B2816232773E66FC00342BB8DD5C965D4B784CD575FDFAF1"
+
+func newConfirmationMock() *sdkMock {
+	m := newSdkMock(0)
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(transactionRoute, confirmationTxHash),
+		RespBody: transactionJson,
+	})
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockByHeightRoute, 42),
+		RespBody: blockInfoJSON,
+	})
+	return m
+}
+
+func blockAt(height int64) *BlockInfo {
+	return &BlockInfo{Height: big.NewInt(height)}
+}
+
+func TestConfirmationWatcher_OnBlock(t *testing.T) {
+	m := newConfirmationMock()
+	defer m.Close()
+
+	var (
+		depths    []uint64
+		confirmed *ConfirmationStatus
+	)
+
+	w, err := NewConfirmationWatcher(m.getTestNetClientUnsafe(), confirmationTxHash, &ConfirmationOptions{
+		Depth:       3,
+		OnDepth:     func(s *ConfirmationStatus) { depths = append(depths, s.Depth) },
+		OnConfirmed: func(s *ConfirmationStatus) { confirmed = s },
+	})
+	assert.Nilf(t, err, "NewConfirmationWatcher returned error: %s", err)
+
+	for h := int64(42); h <= 45; h++ {
+		err = w.OnBlock(ctx, blockAt(h))
+		assert.Nilf(t, err, "ConfirmationWatcher.OnBlock returned error: %s", err)
+
+		if h == 43 {
+			assert.Nil(t, confirmed)
+		}
+	}
+
+	assert.Equal(t, []uint64{1, 2, 3, 4}, depths)
+	assert.NotNil(t, confirmed)
+	assert.Equal(t, big.NewInt(42), confirmed.Height)
+	assert.Equal(t, "83FB2550BDB72B6F507BDBDE90C265D4A324DF9F1EFEFD9F7BD0FDF6391C30D8", confirmed.BlockHash)
+}
+
+func TestConfirmationWatcher_Rollback(t *testing.T) {
+	m := newConfirmationMock()
+	defer m.Close()
+
+	var rolledBack *ConfirmationStatus
+
+	w, err := NewConfirmationWatcher(m.getTestNetClientUnsafe(), confirmationTxHash, &ConfirmationOptions{
+		OnRollback: func(s *ConfirmationStatus) { rolledBack = s },
+	})
+	assert.Nilf(t, err, "NewConfirmationWatcher returned error: %s", err)
+
+	err = w.OnBlock(ctx, blockAt(42))
+	assert.Nilf(t, err, "ConfirmationWatcher.OnBlock returned error: %s", err)
+	assert.Nil(t, rolledBack)
+
+	m.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(blockByHeightRoute, 42),
+		RespBody: strings.Replace(blockInfoJSON, "83FB2550", "00000000", 1),
+	})
+
+	err = w.OnBlock(ctx, blockAt(43))
+	assert.Nilf(t, err, "ConfirmationWatcher.OnBlock returned error: %s", err)
+	assert.NotNil(t, rolledBack)
+	assert.Equal(t, "83FB2550BDB72B6F507BDBDE90C265D4A324DF9F1EFEFD9F7BD0FDF6391C30D8", rolledBack.BlockHash)
+	assert.Equal(t, uint64(2), w.status.Depth)
+	assert.True(t, strings.HasPrefix(w.status.BlockHash, "00000000"))
+}
+
+func TestConfirmationWatcher_NotConfirmed(t *testing.T) {
+	m := newSdkMock(0)
+	defer m.Close()
+
+	called := false
+
+	w, err := NewConfirmationWatcher(m.getTestNetClientUnsafe(), confirmationTxHash, &ConfirmationOptions{
+		OnDepth: func(s *ConfirmationStatus) { called = true },
+	})
+	assert.Nilf(t, err, "NewConfirmationWatcher returned error: %s", err)
+
+	err = w.OnBlock(ctx, blockAt(42))
+	assert.Nilf(t, err, "ConfirmationWatcher.OnBlock returned error: %s", err)
+	assert.False(t, called)
+}
@@ -18,6 +18,27 @@ func (r *RespErr) Error() string {
 	return r.msg
 }
 
+// PublicKeyNotRevealedError is returned when the public key of an account is
+// not known on chain, because the account never signed a transaction.
+type PublicKeyNotRevealedError struct {
+	Address *Address
+}
+
+func (e *PublicKeyNotRevealedError) Error() string {
+	return "public key of " + e.Address.Address + " is not revealed"
+}
+
+// responseStatusError is returned for a response with a status code out of
+// the success range; its message is the body of the response.
+type responseStatusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *responseStatusError) Error() string {
+	return e.body
+}
+
 // Catapult REST API errors
 var (
 	ErrResourceNotFound              = newRespError("resource is not found")
@@ -34,6 +55,12 @@ var (
 	ErrNilMosaicAmount     = errors.New("amount must be not nil")
 	ErrInvalidMosaicName   = errors.New("mosaic name is invalid")
 	ErrNilMosaicProperties = errors.New("mosaic properties must not be nil")
+	ErrInvalidDivisibility = errors.New("divisibility should be between 0 and 6 and equal for both amounts")
+	ErrInvalidAmount       = errors.New("amount is not a decimal number with at most divisibility decimals")
+	ErrMosaicMismatch      = errors.New("amounts are of different mosaics")
+	ErrInvalidLevyType     = errors.New("levy type should be LevyAbsolute or LevyPercentile")
+	ErrInvalidLevyFee      = errors.New("levy fee should not be nil or negative")
+	ErrMissingMosaicInfo   = errors.New("mosaic info of a transferred mosaic is missing")
 )
 
 // Namespace errors
@@ -42,12 +69,27 @@ var (
 	ErrNilNamespaceId       = errors.New("namespaceId is nil or zero")
 	ErrEmptyNamespaceIds    = errors.New("list namespace ids must not by empty")
 	ErrInvalidNamespaceName = errors.New("namespace name is invalid")
+	// ErrNameIdMismatch is returned when a name does not generate the ID it
+	// is given with
+	ErrNameIdMismatch = errors.New("name does not match its id")
+	// ErrNotRootNamespace is returned when renewing a sub-namespace,
+	// which lives as long as its root namespace
+	ErrNotRootNamespace          = errors.New("namespace is not a root namespace")
+	ErrNamespaceRenewalNotNeeded = errors.New("namespace lives long enough, renewal not needed")
+	// SkipNamespace is returned by the function of NamespaceNode.Walk to
+	// skip the children of a namespace; it is not returned as an error
+	SkipNamespace = errors.New("skip this namespace")
 )
 
 // Blockchain errors
 var (
-	ErrNilOrZeroHeight = errors.New("block height should not be nil or zero")
-	ErrNilOrZeroLimit  = errors.New("limit should not be nil or zero")
+	ErrNilOrZeroHeight    = errors.New("block height should not be nil or zero")
+	ErrNilOrZeroLimit     = errors.New("limit should not be nil or zero")
+	ErrInvalidHeightRange = errors.New("from height should not be above to height")
+	ErrReorgTooDeep       = errors.New("fork point is older than the blocks kept")
+	// ErrMissingBlockTransactions is returned when the node returns fewer
+	// transactions than the number of transactions of the block
+	ErrMissingBlockTransactions = errors.New("block transactions are missing")
 )
 
 var (
@@ -56,6 +98,51 @@ var (
 	ErrBlankAddress      = errors.New("address is blank")
 	ErrNilAccount        = errors.New("account should not be nil")
 	ErrInvalidAddress    = errors.New("wrong address")
+
+	ErrInvalidAddressLength   = errors.New("address should have 40 characters without hyphens")
+	ErrInvalidAddressEncoding = errors.New("address is not valid base32")
+	ErrInvalidAddressNetwork  = errors.New("address network byte is unknown")
+	ErrInvalidAddressChecksum = errors.New("address checksum is wrong")
+
+	ErrInvalidHistoryFormat = errors.New("history format should be HistoryCSV or HistoryJSONLines")
+)
+
+// Multisig errors
+var (
+	ErrNilMultisigAccountInfo = errors.New("multisig account info should not be nil")
+	ErrNilMultisigTarget      = errors.New("multisig target should not be nil")
+	ErrSelfCosignatory        = errors.New("account can not be its own cosignatory")
+	ErrDuplicateCosignatory   = errors.New("cosignatory is listed more than once")
+	ErrInvalidMinApproval     = errors.New("min approval should be between 1 and the number of cosignatories")
+	ErrInvalidMinRemoval      = errors.New("min removal should be between 1 and the number of cosignatories")
+)
+
+// Vanity address errors
+var (
+	ErrNilVanityOptions     = errors.New("vanity options should not be nil")
+	ErrEmptyVanityPattern   = errors.New("vanity prefix, suffix or regexp should be set")
+	ErrInvalidVanityPattern = errors.New("vanity pattern can not match any address of the network")
+)
+
+// Payment errors
+var (
+	ErrBlankPaymentReference = errors.New("payment reference should not be blank")
+)
+
+// Transaction errors
+var (
+	ErrNilSignedTransaction = errors.New("signed transaction should not be nil")
+	ErrInvalidSignedPayload = errors.New("signed transaction payload is not valid")
+	ErrBlankHash            = errors.New("transaction hash is blank")
+	ErrNilRemoteAccount     = errors.New("remote account should not be nil")
+	ErrInvalidLinkAction    = errors.New("link action should be LinkAccount or UnlinkAccount")
+)
+
+// Client errors
+var (
+	ErrNilClient           = errors.New("client should not be nil")
+	ErrInvalidWebsocketURL = errors.New("websocket url should have a host and a ws, wss, http or https scheme")
+	ErrWebsocketTimeout    = errors.New("timed out connecting to the websocket")
 )
 
 // NetworkType error
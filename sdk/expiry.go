@@ -0,0 +1,397 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"golang.org/x/net/context"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBlockTime is the target block time of Catapult networks.
+	DefaultBlockTime = 15 * time.Second
+
+	defaultBlockTimeSample    = 60
+	defaultNamespacesPageSize = 100
+)
+
+// eternalHeight is the end height of namespaces which never expire, such as
+// the nemesis namespaces.
+var eternalHeight = new(big.Int).SetUint64(^uint64(0))
+
+// NamespaceExpiry is the remaining lifetime of a namespace.
+type NamespaceExpiry struct {
+	Namespace *NamespaceInfo
+	// Name is the full name of the namespace, needed to renew it.
+	Name string
+	// BlocksLeft is zero once the namespace expired.
+	BlocksLeft *big.Int
+	// TimeLeft is BlocksLeft at the average block time.
+	TimeLeft time.Duration
+	Expired  bool
+	// Eternal namespaces never expire.
+	Eternal bool
+	// Threshold is the smallest of NamespaceMonitorOptions.Thresholds which
+	// TimeLeft reached, zero if none.
+	Threshold time.Duration
+}
+
+// IsRoot reports whether the namespace is a root namespace, which is the
+// one to renew; sub-namespaces expire with their root.
+func (e *NamespaceExpiry) IsRoot() bool {
+	return e.Namespace.TypeSpace == Root
+}
+
+// NamespaceMonitorOptions configures a NamespaceMonitor; zero values mean
+// defaults.
+type NamespaceMonitorOptions struct {
+	// BlockTime is the average block time. By default it is measured over
+	// the last BlockTimeSample blocks on every check.
+	BlockTime time.Duration
+	// BlockTimeSample is the number of blocks the block time is measured
+	// over, 60 by default.
+	BlockTimeSample int64
+	// Thresholds of time left which raise a warning, 30, 7 and 1 day by
+	// default. Each threshold warns once per namespace, until it is renewed.
+	Thresholds []time.Duration
+	// OnWarning is called with the namespaces which reached a new threshold
+	// or expired.
+	OnWarning func(*NamespaceExpiry)
+	// OnError is called by Watch with the error of a failed check; the next
+	// check is done at the next tick anyway.
+	OnError func(error)
+	// PageSize of the namespaces requests, 100 by default.
+	PageSize int
+}
+
+// NamespaceMonitor watches the expiry of the namespaces owned by a set of
+// accounts.
+type NamespaceMonitor struct {
+	client    *Client
+	addresses []*Address
+	opts      NamespaceMonitorOptions
+
+	mu sync.Mutex
+	// warned is the smallest threshold warned of, by namespace id.
+	warned map[string]time.Duration
+}
+
+// NewNamespaceMonitor returns a monitor of the namespaces owned by addresses.
+func NewNamespaceMonitor(client *Client, addresses []*Address, opts *NamespaceMonitorOptions) (*NamespaceMonitor, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	if len(addresses) == 0 {
+		return nil, ErrEmptyAddressesIds
+	}
+
+	for _, address := range addresses {
+		if address == nil {
+			return nil, ErrNilAddress
+		}
+	}
+
+	m := &NamespaceMonitor{
+		client:    client,
+		addresses: addresses,
+		warned:    make(map[string]time.Duration),
+	}
+
+	if opts != nil {
+		m.opts = *opts
+	}
+	if m.opts.BlockTimeSample <= 0 {
+		m.opts.BlockTimeSample = defaultBlockTimeSample
+	}
+	if m.opts.PageSize <= 0 {
+		m.opts.PageSize = defaultNamespacesPageSize
+	}
+	if len(m.opts.Thresholds) == 0 {
+		m.opts.Thresholds = []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour}
+	}
+
+	thresholds := append([]time.Duration(nil), m.opts.Thresholds...)
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] > thresholds[j] })
+	m.opts.Thresholds = thresholds
+
+	return m, nil
+}
+
+// Check returns the expiry of the namespaces owned by the accounts, the
+// soonest first, and calls OnWarning for those which reached a new threshold.
+func (m *NamespaceMonitor) Check(ctx context.Context) ([]*NamespaceExpiry, error) {
+	height, err := m.client.Blockchain.GetBlockchainHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockTime, err := m.blockTime(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	nsInfos, err := m.namespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := m.names(ctx, nsInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	expiries := make([]*NamespaceExpiry, len(nsInfos))
+	for i, nsInfo := range nsInfos {
+		expiries[i] = m.expiry(nsInfo, height, blockTime)
+		expiries[i].Name = names[nsInfo.NamespaceId.String()]
+	}
+
+	sort.SliceStable(expiries, func(i, j int) bool {
+		if expiries[i].Eternal != expiries[j].Eternal {
+			return expiries[j].Eternal
+		}
+		return expiries[i].BlocksLeft.Cmp(expiries[j].BlocksLeft) < 0
+	})
+
+	for _, e := range m.warnings(expiries) {
+		m.opts.OnWarning(e)
+	}
+
+	return expiries, nil
+}
+
+// Watch checks the namespaces every interval until the context is done. A
+// failed check, e.g. while the node is unreachable, is reported to OnError
+// and does not stop the watch.
+func (m *NamespaceMonitor) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil && m.opts.OnError != nil {
+			m.opts.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RenewalTransaction returns the transaction which extends the root
+// namespace of e so that it lives at least keepFor from the last check.
+func (m *NamespaceMonitor) RenewalTransaction(deadline *Deadline, e *NamespaceExpiry, keepFor time.Duration) (*RegisterNamespaceTransaction, error) {
+	if e == nil || e.Namespace == nil {
+		return nil, ErrNilNamespaceId
+	}
+
+	if !e.IsRoot() {
+		return nil, ErrNotRootNamespace
+	}
+
+	if e.Name == "" {
+		return nil, ErrInvalidNamespaceName
+	}
+
+	blockTime := m.opts.BlockTime
+	if e.BlocksLeft.Sign() > 0 {
+		blockTime = e.TimeLeft / time.Duration(e.BlocksLeft.Int64())
+	}
+	if blockTime <= 0 {
+		blockTime = DefaultBlockTime
+	}
+
+	needed := big.NewInt(int64((keepFor + blockTime - 1) / blockTime))
+	duration := needed.Sub(needed, e.BlocksLeft)
+
+	if e.Eternal || duration.Sign() <= 0 {
+		return nil, ErrNamespaceRenewalNotNeeded
+	}
+
+	return NewRegisterRootNamespaceTransaction(deadline, e.Name, duration, m.client.config.NetworkType)
+}
+
+func (m *NamespaceMonitor) expiry(nsInfo *NamespaceInfo, height *big.Int, blockTime time.Duration) *NamespaceExpiry {
+	e := &NamespaceExpiry{
+		Namespace:  nsInfo,
+		BlocksLeft: big.NewInt(0),
+	}
+
+	if nsInfo.EndHeight == nil || nsInfo.EndHeight.Cmp(eternalHeight) >= 0 {
+		e.Eternal = true
+		return e
+	}
+
+	if nsInfo.EndHeight.Cmp(height) <= 0 {
+		e.Expired = true
+	} else {
+		e.BlocksLeft.Sub(nsInfo.EndHeight, height)
+		e.TimeLeft = time.Duration(e.BlocksLeft.Int64()) * blockTime
+	}
+
+	for _, threshold := range m.opts.Thresholds {
+		if e.TimeLeft <= threshold {
+			e.Threshold = threshold
+		}
+	}
+
+	return e
+}
+
+// warnings returns the expiries which reached a smaller threshold than
+// already warned of, and forgets the warnings of renewed namespaces.
+func (m *NamespaceMonitor) warnings(expiries []*NamespaceExpiry) []*NamespaceExpiry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	warn := make([]*NamespaceExpiry, 0)
+
+	for _, e := range expiries {
+		id := e.Namespace.NamespaceId.String()
+
+		if e.Threshold == 0 && !e.Expired {
+			delete(m.warned, id)
+			continue
+		}
+
+		// expired is past every threshold
+		level := e.Threshold
+		if e.Expired {
+			level = -1
+		}
+
+		warned, ok := m.warned[id]
+		m.warned[id] = level
+
+		if ok && warned <= level {
+			continue
+		}
+
+		if m.opts.OnWarning != nil {
+			warn = append(warn, e)
+		}
+	}
+
+	return warn
+}
+
+// blockTime measures the average block time over the last blocks.
+func (m *NamespaceMonitor) blockTime(ctx context.Context, height *big.Int) (time.Duration, error) {
+	if m.opts.BlockTime > 0 {
+		return m.opts.BlockTime, nil
+	}
+
+	from := new(big.Int).Sub(height, big.NewInt(m.opts.BlockTimeSample))
+	if from.Cmp(big.NewInt(1)) < 0 {
+		from = big.NewInt(1)
+	}
+
+	blocks := new(big.Int).Sub(height, from).Int64()
+	if blocks == 0 {
+		return DefaultBlockTime, nil
+	}
+
+	first, err := m.client.Blockchain.GetBlockByHeight(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := m.client.Blockchain.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+
+	ms := new(big.Int).Sub(last.Timestamp, first.Timestamp).Int64() / blocks
+	if ms <= 0 {
+		return DefaultBlockTime, nil
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// namespaces pages through the namespaces of the accounts.
+func (m *NamespaceMonitor) namespaces(ctx context.Context) ([]*NamespaceInfo, error) {
+	nsInfos := make([]*NamespaceInfo, 0)
+	seen := make(map[string]bool)
+
+	var last *NamespaceId
+	for {
+		page, err := m.client.Namespace.GetNamespacesFromAccounts(ctx, m.addresses, last, m.opts.PageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, nsInfo := range page {
+			if nsInfo.NamespaceId == nil || seen[nsInfo.NamespaceId.String()] {
+				continue
+			}
+
+			seen[nsInfo.NamespaceId.String()] = true
+			nsInfos = append(nsInfos, nsInfo)
+			added++
+		}
+
+		if len(page) < m.opts.PageSize || added == 0 {
+			return nsInfos, nil
+		}
+
+		last = page[len(page)-1].NamespaceId
+	}
+}
+
+// names returns the full names of the namespaces, by namespace id.
+func (m *NamespaceMonitor) names(ctx context.Context, nsInfos []*NamespaceInfo) (map[string]string, error) {
+	ids := make(map[string]*NamespaceId)
+	for _, nsInfo := range nsInfos {
+		for _, level := range nsInfo.Levels {
+			ids[level.String()] = level
+		}
+	}
+
+	names := make(map[string]string, len(nsInfos))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	nsIds := make([]*NamespaceId, 0, len(ids))
+	for _, nsId := range ids {
+		nsIds = append(nsIds, nsId)
+	}
+
+	nsNames, err := m.client.Namespace.GetNamespaceNames(ctx, nsIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]string, len(nsNames))
+	for _, nsName := range nsNames {
+		byId[nsName.NamespaceId.String()] = nsName.Name
+	}
+
+	for _, nsInfo := range nsInfos {
+		parts := make([]string, 0, len(nsInfo.Levels))
+		for _, level := range nsInfo.Levels {
+			name, ok := byId[level.String()]
+			if !ok {
+				break
+			}
+			parts = append(parts, name)
+		}
+
+		if len(parts) == len(nsInfo.Levels) && len(parts) > 0 {
+			names[nsInfo.NamespaceId.String()] = strings.Join(parts, ".")
+		}
+	}
+
+	return names, nil
+}
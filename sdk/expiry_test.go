@@ -0,0 +1,139 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamespaceMonitor(t *testing.T) {
+	// foo (1) and foo.bar (2), baz (3) expired and nem (4) eternal
+	foo := testNamespaceJSON(1, 0, "[6000, 0]")
+	namespaces := []string{
+		foo,
+		testNamespaceJSON(2, 1, "[6000, 0]"),
+		testNamespaceJSON(3, 0, "[900, 0]"),
+		testNamespaceJSON(4, 0, "[4294967295, 4294967295]"),
+	}
+	names := []string{
+		`{"namespaceId": [1, 0], "name": "foo", "parentId": [0, 0]}`,
+		`{"namespaceId": [2, 0], "name": "bar", "parentId": [1, 0]}`,
+		`{"namespaceId": [3, 0], "name": "baz", "parentId": [0, 0]}`,
+		`{"namespaceId": [4, 0], "name": "nem", "parentId": [0, 0]}`,
+	}
+
+	node := newSdkMockWithRouter(&mock.Router{
+		Path:     blockHeightRoute,
+		RespBody: `{"height": [1000, 0]}`,
+	})
+	defer node.Close()
+
+	// the block time is measured over blocks 940 to 1000
+	for _, router := range testBlockRouters(940, 1000) {
+		node.AddRouter(router)
+	}
+	node.AddRouter(&mock.Router{
+		Path:     fmt.Sprintf(namespaceRoute, BigIntegerToHex(big.NewInt(1))),
+		RespBody: foo,
+	})
+	node.AddRouter(&mock.Router{
+		Path:     namespacesFromAccountsRoute,
+		RespBody: "[" + strings.Join(namespaces, ",") + "]",
+	})
+	node.AddRouter(&mock.Router{
+		Path:     namespaceNamesRoute,
+		RespBody: "[" + strings.Join(names, ",") + "]",
+	})
+
+	client := node.getTestNetClientUnsafe()
+	warnings := make([]*NamespaceExpiry, 0)
+
+	m, err := NewNamespaceMonitor(client, []*Address{{TestNet, nemTestAddress1}}, &NamespaceMonitorOptions{
+		Thresholds: []time.Duration{24 * time.Hour, 30 * 24 * time.Hour},
+		OnWarning: func(e *NamespaceExpiry) {
+			warnings = append(warnings, e)
+		},
+	})
+	assert.Nil(t, err)
+
+	expiries, err := m.Check(ctx)
+	assert.Nilf(t, err, "NamespaceMonitor.Check returned error: %s", err)
+	assert.Len(t, expiries, 4)
+
+	baz, foo1, bar, nem := expiries[0], expiries[1], expiries[2], expiries[3]
+
+	assert.Equal(t, "baz", baz.Name)
+	assert.True(t, baz.Expired)
+	assert.Equal(t, time.Duration(0), baz.TimeLeft)
+
+	// 5000 blocks of 1 second
+	assert.Equal(t, "foo", foo1.Name)
+	assert.Equal(t, big.NewInt(5000), foo1.BlocksLeft)
+	assert.Equal(t, 5000*time.Second, foo1.TimeLeft)
+	assert.Equal(t, 24*time.Hour, foo1.Threshold)
+
+	assert.Equal(t, "foo.bar", bar.Name)
+	assert.False(t, bar.IsRoot())
+
+	assert.Equal(t, "nem", nem.Name)
+	assert.True(t, nem.Eternal)
+
+	assert.Equal(t, []*NamespaceExpiry{baz, foo1, bar}, warnings)
+
+	// the thresholds were already warned of
+	_, err = m.Check(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, warnings, 3)
+
+	tx, err := m.RenewalTransaction(fakeDeadline, foo1, 365*24*time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", tx.NamspaceName)
+	assert.Equal(t, big.NewInt(365*24*60*60-5000), tx.Duration)
+	assert.Equal(t, TestNet, tx.NetworkType)
+
+	_, err = m.RenewalTransaction(fakeDeadline, foo1, time.Hour)
+	assert.Equal(t, ErrNamespaceRenewalNotNeeded, err)
+
+	_, err = m.RenewalTransaction(fakeDeadline, bar, 365*24*time.Hour)
+	assert.Equal(t, ErrNotRootNamespace, err)
+
+	_, err = m.RenewalTransaction(fakeDeadline, nem, 365*24*time.Hour)
+	assert.Equal(t, ErrNamespaceRenewalNotNeeded, err)
+
+	_, err = NewNamespaceMonitor(nil, []*Address{{TestNet, nemTestAddress1}}, nil)
+	assert.Equal(t, ErrNilClient, err)
+}
+
+func TestNamespaceMonitor_Watch(t *testing.T) {
+	// the node serves nothing, so every check fails
+	node := newSdkMock(0)
+	defer node.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := 0
+	m, err := NewNamespaceMonitor(node.getTestNetClientUnsafe(), []*Address{{TestNet, nemTestAddress1}}, &NamespaceMonitorOptions{
+		OnError: func(err error) {
+			assert.NotNil(t, err)
+
+			errs++
+			if errs == 3 {
+				cancel()
+			}
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, context.Canceled, m.Watch(ctx, time.Millisecond))
+	assert.Equal(t, 3, errs)
+}
@@ -0,0 +1,465 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"encoding/csv"
+	"errors"
+	"golang.org/x/net/context"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultHistoryPageSize = 100
+
+// HistoryFormat is the output format of AccountService.ExportHistory.
+type HistoryFormat uint8
+
+const (
+	// HistoryCSV writes a header line and one comma separated line per row.
+	HistoryCSV HistoryFormat = iota
+	// HistoryJSONLines writes one JSON object per line.
+	HistoryJSONLines
+)
+
+// HistoryFilter selects the rows of an account history. Its zero value
+// selects every row.
+type HistoryFilter struct {
+	// From and To bound the block timestamps, From included and To excluded.
+	// Zero values leave the range open.
+	From, To time.Time
+	// FromHeight and ToHeight bound the block heights, both included.
+	// Nil values leave the range open.
+	FromHeight, ToHeight *big.Int
+	// Types keeps the transactions of these types only. The inner
+	// transactions of aggregates are matched by their own type.
+	Types []TransactionType
+	// MosaicIds keeps the legs of these mosaics only, which leaves out the
+	// transactions moving no mosaic.
+	MosaicIds []*MosaicId
+}
+
+// HistoryOptions configures AccountService.ExportHistory.
+type HistoryOptions struct {
+	Format HistoryFormat
+	Filter HistoryFilter
+	// PageSize is the number of transactions requested at a time, 100 by default.
+	PageSize int
+}
+
+// HistoryRow is one leg of a transaction of an account history: a transfer
+// transaction with two mosaics makes two rows. Transactions moving no
+// mosaic make a single row without Amount.
+type HistoryRow struct {
+	// Timestamp is the time of the block including the transaction.
+	Timestamp time.Time
+	Height    *big.Int
+	// Hash is the hash of the transaction, or of its aggregate for inner
+	// transactions.
+	Hash string
+	Type TransactionType
+	// Incoming is true when the account received Amount, false when it sent
+	// it or signed the transaction.
+	Incoming bool
+	// Counterparty is the other account of the leg, nil if there is none.
+	Counterparty *Address
+	Amount       *Amount
+	Message      string
+}
+
+var transactionTypeNames = map[TransactionType]string{
+	AggregateCompleted: "aggregate_complete",
+	AggregateBonded:    "aggregate_bonded",
+	MosaicDefinition:   "mosaic_definition",
+	MosaicSupplyChange: "mosaic_supply_change",
+	ModifyMultisig:     "modify_multisig",
+	RegisterNamespace:  "register_namespace",
+	Transfer:           "transfer",
+	Lock:               "lock",
+	SecretLock:         "secret_lock",
+	SecretProof:        "secret_proof",
+	AccountLink:        "account_link",
+}
+
+// errHistoryDone stops the paging once the rows are older than the filter.
+var errHistoryDone = errors.New("history done")
+
+// History calls fn with the rows of the confirmed transactions of account
+// which match filter, newest first, fetching pageSize transactions at a time.
+// It stops with the error of fn, if any.
+func (a *AccountService) History(ctx context.Context, account *PublicAccount, filter *HistoryFilter, pageSize int, fn func(*HistoryRow) error) error {
+	if account == nil || account.Address == nil {
+		return ErrNilAccount
+	}
+	if filter == nil {
+		filter = &HistoryFilter{}
+	}
+	if pageSize <= 0 {
+		pageSize = defaultHistoryPageSize
+	}
+
+	h := &historyReader{
+		client:  a.client,
+		account: account,
+		filter:  filter,
+		fn:      fn,
+	}
+
+	opt := &AccountTransactionsOption{PageSize: pageSize}
+
+	for {
+		txs, err := a.Transactions(ctx, account, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			if err := h.read(ctx, tx); err == errHistoryDone {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if len(txs) < pageSize {
+			return nil
+		}
+
+		info := txs[len(txs)-1].GetAbstractTransaction().TransactionInfo
+		if info == nil || info.Id == "" {
+			return nil
+		}
+		opt = &AccountTransactionsOption{PageSize: pageSize, Id: info.Id}
+	}
+}
+
+// ExportHistory writes the history of account to w in opts.Format, newest
+// first, and returns the number of rows written. Timestamps are RFC 3339 in
+// UTC and amounts are decimal, e.g. 12.345678.
+func (a *AccountService) ExportHistory(ctx context.Context, account *PublicAccount, w io.Writer, opts *HistoryOptions) (int, error) {
+	if opts == nil {
+		opts = &HistoryOptions{}
+	}
+
+	var hw historyWriter
+	switch opts.Format {
+	case HistoryCSV:
+		hw = newHistoryCSVWriter(w)
+	case HistoryJSONLines:
+		hw = &historyJSONWriter{json.NewEncoder(w)}
+	default:
+		return 0, ErrInvalidHistoryFormat
+	}
+
+	n := 0
+	err := a.History(ctx, account, &opts.Filter, opts.PageSize, func(row *HistoryRow) error {
+		if err := hw.write(row); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	return n, hw.flush()
+}
+
+type historyReader struct {
+	client  *Client
+	account *PublicAccount
+	filter  *HistoryFilter
+	fn      func(*HistoryRow) error
+
+	// consecutive transactions are mostly of the same block
+	height    *big.Int
+	timestamp time.Time
+}
+
+func (h *historyReader) read(ctx context.Context, tx Transaction) error {
+	info := tx.GetAbstractTransaction().TransactionInfo
+	if info == nil || info.Height == nil {
+		return nil
+	}
+
+	f := h.filter
+	if f.ToHeight != nil && info.Height.Cmp(f.ToHeight) > 0 {
+		return nil
+	}
+	if f.FromHeight != nil && info.Height.Cmp(f.FromHeight) < 0 {
+		return errHistoryDone
+	}
+
+	ts, err := h.blockTime(ctx, info.Height)
+	if err != nil {
+		return err
+	}
+
+	if !f.To.IsZero() && !ts.Before(f.To) {
+		return nil
+	}
+	if !f.From.IsZero() && ts.Before(f.From) {
+		return errHistoryDone
+	}
+
+	rows := make([]*HistoryRow, 0)
+	if atx, ok := tx.(*AggregateTransaction); ok {
+		for _, inner := range atx.InnerTransactions {
+			// the aggregate lists inner transactions of other accounts too
+			if !h.involves(inner) {
+				continue
+			}
+			rows = append(rows, h.legs(inner)...)
+		}
+	} else {
+		rows = h.legs(tx)
+	}
+
+	for _, row := range rows {
+		row.Timestamp, row.Height, row.Hash = ts, info.Height, info.Hash.String()
+
+		if !h.matchType(row.Type) || !h.matchMosaic(row.Amount) {
+			continue
+		}
+
+		if row.Amount != nil {
+			d, err := h.client.Mosaic.GetMosaicDivisibility(ctx, row.Amount.MosaicId)
+			if err != nil {
+				return err
+			}
+			row.Amount.Divisibility = d
+		}
+
+		if err := h.fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *historyReader) blockTime(ctx context.Context, height *big.Int) (time.Time, error) {
+	if h.height != nil && h.height.Cmp(height) == 0 {
+		return h.timestamp, nil
+	}
+
+	block, err := h.client.Blockchain.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	h.height = height
+	h.timestamp = TimestampNemesisBlock.Add(time.Duration(block.Timestamp.Int64()) * time.Millisecond).UTC()
+	return h.timestamp, nil
+}
+
+func (h *historyReader) involves(tx Transaction) bool {
+	if h.isAccount(tx.GetAbstractTransaction().Signer) {
+		return true
+	}
+
+	switch t := tx.(type) {
+	case *TransferTransaction:
+		return h.account.Address.Equals(t.Recipient)
+	case *SecretLockTransaction:
+		return h.account.Address.Equals(t.Recipient)
+	}
+	return false
+}
+
+// isAccount compares public keys, as the address of a signer is derived for
+// the network of the transaction version.
+func (h *historyReader) isAccount(acc *PublicAccount) bool {
+	return acc != nil && strings.EqualFold(h.account.PublicKey, acc.PublicKey)
+}
+
+// legs returns the rows of tx, without block information and divisibility.
+func (h *historyReader) legs(tx Transaction) []*HistoryRow {
+	abs := tx.GetAbstractTransaction()
+
+	var signer *Address
+	if abs.Signer != nil {
+		signer = abs.Signer.Address
+	}
+	outgoing := h.isAccount(abs.Signer)
+
+	leg := func(counterparty *Address, mosaic *Mosaic) *HistoryRow {
+		row := &HistoryRow{Type: abs.Type, Incoming: !outgoing, Counterparty: counterparty}
+		if mosaic != nil {
+			row.Amount = &Amount{MosaicId: mosaic.MosaicId, Raw: mosaic.Amount}
+		}
+		return row
+	}
+
+	// the counterparty of an incoming leg is the signer
+	counterparty := func(recipient *Address) *Address {
+		if outgoing {
+			return recipient
+		}
+		return signer
+	}
+
+	switch t := tx.(type) {
+	case *TransferTransaction:
+		var message string
+		if t.Message != nil {
+			message = t.Message.Payload
+		}
+
+		if len(t.Mosaics) == 0 {
+			row := leg(counterparty(t.Recipient), nil)
+			row.Message = message
+			return []*HistoryRow{row}
+		}
+
+		rows := make([]*HistoryRow, len(t.Mosaics))
+		for i, m := range t.Mosaics {
+			rows[i] = leg(counterparty(t.Recipient), m)
+			rows[i].Message = message
+		}
+		return rows
+	case *SecretLockTransaction:
+		return []*HistoryRow{leg(counterparty(t.Recipient), t.Mosaic)}
+	case *LockFundsTransaction:
+		return []*HistoryRow{leg(nil, t.Mosaic)}
+	default:
+		row := leg(nil, nil)
+		if !outgoing {
+			row.Counterparty = signer
+		}
+		return []*HistoryRow{row}
+	}
+}
+
+func (h *historyReader) matchType(t TransactionType) bool {
+	if len(h.filter.Types) == 0 {
+		return true
+	}
+
+	for _, ft := range h.filter.Types {
+		if ft == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *historyReader) matchMosaic(amount *Amount) bool {
+	if len(h.filter.MosaicIds) == 0 {
+		return true
+	}
+	if amount == nil {
+		return false
+	}
+
+	for _, id := range h.filter.MosaicIds {
+		if mosaicIdToBigInt(id).Cmp(mosaicIdToBigInt(amount.MosaicId)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// historyRecord is the flat form of a HistoryRow which is exported.
+type historyRecord struct {
+	Timestamp    string `json:"timestamp"`
+	Height       string `json:"height"`
+	Hash         string `json:"hash"`
+	Type         string `json:"type"`
+	Direction    string `json:"direction"`
+	Counterparty string `json:"counterparty"`
+	Mosaic       string `json:"mosaic"`
+	Amount       string `json:"amount"`
+	Message      string `json:"message"`
+}
+
+var historyColumns = []string{"timestamp", "height", "hash", "type", "direction", "counterparty", "mosaic", "amount", "message"}
+
+func newHistoryRecord(row *HistoryRow) *historyRecord {
+	r := &historyRecord{
+		Timestamp: row.Timestamp.Format(time.RFC3339),
+		Height:    row.Height.String(),
+		Hash:      row.Hash,
+		Type:      transactionTypeNames[row.Type],
+		Direction: "out",
+		Message:   row.Message,
+	}
+
+	if r.Type == "" {
+		r.Type = strconv.FormatUint(uint64(row.Type.Raw()), 10)
+	}
+	if row.Incoming {
+		r.Direction = "in"
+	}
+	if row.Counterparty != nil {
+		r.Counterparty = row.Counterparty.Address
+	}
+	if row.Amount != nil {
+		r.Mosaic = row.Amount.MosaicId.toHexString()
+		r.Amount = row.Amount.String()
+	}
+
+	return r
+}
+
+type historyWriter interface {
+	write(row *HistoryRow) error
+	flush() error
+}
+
+type historyCSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newHistoryCSVWriter(w io.Writer) *historyCSVWriter {
+	return &historyCSVWriter{w: csv.NewWriter(w)}
+}
+
+func (hw *historyCSVWriter) write(row *HistoryRow) error {
+	if err := hw.writeHeader(); err != nil {
+		return err
+	}
+
+	r := newHistoryRecord(row)
+	return hw.w.Write([]string{r.Timestamp, r.Height, r.Hash, r.Type, r.Direction, r.Counterparty, r.Mosaic, r.Amount, r.Message})
+}
+
+func (hw *historyCSVWriter) writeHeader() error {
+	if hw.header {
+		return nil
+	}
+	hw.header = true
+
+	return hw.w.Write(historyColumns)
+}
+
+func (hw *historyCSVWriter) flush() error {
+	// an empty history still gets its header
+	if err := hw.writeHeader(); err != nil {
+		return err
+	}
+
+	hw.w.Flush()
+	return hw.w.Error()
+}
+
+type historyJSONWriter struct {
+	enc interface {
+		Encode(v interface{}) error
+	}
+}
+
+func (hw *historyJSONWriter) write(row *HistoryRow) error {
+	return hw.enc.Encode(newHistoryRecord(row))
+}
+
+func (hw *historyJSONWriter) flush() error {
+	return nil
+}
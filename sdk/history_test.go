@@ -0,0 +1,117 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const historyTestSigner = "321DE652C4D3362FC2DDF7800F6582F4A10CFEA134B81F8AB6E4BE78BBA4D18E"
+
+// newHistoryTestClient serves a page of the transactions of account at
+// heights 50, 40 and 30 and their blocks.
+func newHistoryTestClient(t *testing.T) (*Client, *PublicAccount, *sdkMock) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+	other, err := NewAccountFromPublicKey(historyTestSigner, TestNet)
+	assert.Nil(t, err)
+
+	xem := bigIntToMosaicId(uint64DTO{3646934825, 3576016193}.toBigInt())
+
+	txs := []string{
+		testTransferJSON(t, 50, account.PublicKey, other.Address, "invoice 42",
+			&Mosaic{xem, big.NewInt(12500000)}, &Mosaic{testMosaicIds[1], big.NewInt(3)}),
+		testTransferJSON(t, 40, account.PublicKey, other.Address, "", &Mosaic{xem, big.NewInt(1)}),
+		testTransferJSON(t, 30, historyTestSigner, account.Address, "refund, \"partial\"", &Mosaic{xem, big.NewInt(2000000)}),
+	}
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(transactionsByAccountRoute, account.PublicKey, accountTransactionsRoute),
+		RespBody: "[" + strings.Join(txs, ",") + "]",
+	})
+	for _, router := range testBlockRouters(50, 40, 30) {
+		m.AddRouter(router)
+	}
+
+	client := m.getTestNetClientUnsafe()
+	client.divisibilities.set(XemMosaicId, XemDivisibility)
+	client.divisibilities.set(testMosaicIds[1], 0)
+
+	return client, account, m
+}
+
+func TestAccountService_ExportHistory(t *testing.T) {
+	client, account, m := newHistoryTestClient(t)
+	defer m.Close()
+
+	other, err := NewAccountFromPublicKey(historyTestSigner, TestNet)
+	assert.Nil(t, err)
+
+	var b bytes.Buffer
+	n, err := client.Account.ExportHistory(ctx, account, &b, nil)
+	assert.Nilf(t, err, "AccountService.ExportHistory returned error: %s", err)
+	assert.Equal(t, 4, n)
+
+	at := func(height int) string {
+		return TimestampNemesisBlock.Add(time.Duration(height) * time.Second).UTC().Format(time.RFC3339)
+	}
+
+	expected := strings.Join([]string{
+		"timestamp,height,hash,type,direction,counterparty,mosaic,amount,message",
+		fmt.Sprintf("%s,50,%064X,transfer,out,%s,d525ad41d95fcf29,12.500000,invoice 42", at(50), 50, other.Address.Address),
+		fmt.Sprintf("%s,50,%064X,transfer,out,%s,%s,3,invoice 42", at(50), 50, other.Address.Address, testMosaicIds[1].toHexString()),
+		fmt.Sprintf("%s,40,%064X,transfer,out,%s,d525ad41d95fcf29,0.000001,", at(40), 40, other.Address.Address),
+		fmt.Sprintf("%s,30,%064X,transfer,in,%s,d525ad41d95fcf29,2.000000,\"refund, \"\"partial\"\"\"", at(30), 30, other.Address.Address),
+		"",
+	}, "\n")
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAccountService_ExportHistoryFilters(t *testing.T) {
+	client, account, m := newHistoryTestClient(t)
+	defer m.Close()
+
+	var b bytes.Buffer
+	n, err := client.Account.ExportHistory(ctx, account, &b, &HistoryOptions{
+		Format: HistoryJSONLines,
+		Filter: HistoryFilter{
+			To:         TimestampNemesisBlock.Add(50 * time.Second),
+			FromHeight: big.NewInt(35),
+			MosaicIds:  []*MosaicId{XemMosaicId},
+		},
+	})
+	assert.Nilf(t, err, "AccountService.ExportHistory returned error: %s", err)
+	assert.Equal(t, 1, n)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 1)
+
+	record := &historyRecord{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), record))
+	assert.Equal(t, "40", record.Height)
+	assert.Equal(t, "0.000001", record.Amount)
+	assert.Equal(t, "out", record.Direction)
+
+	b.Reset()
+	n, err = client.Account.ExportHistory(ctx, account, &b, &HistoryOptions{
+		Filter: HistoryFilter{Types: []TransactionType{AggregateCompleted}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "timestamp,height,hash,type,direction,counterparty,mosaic,amount,message\n", b.String())
+
+	_, err = client.Account.ExportHistory(ctx, account, &b, &HistoryOptions{Format: HistoryJSONLines + 1})
+	assert.Equal(t, ErrInvalidHistoryFormat, err)
+
+	_, err = client.Account.ExportHistory(ctx, nil, &b, nil)
+	assert.Equal(t, ErrNilAccount, err)
+}
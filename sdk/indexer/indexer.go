@@ -0,0 +1,368 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package indexer keeps a local index of the chain in an embedded bbolt
+// database, for queries the REST API does not offer, such as the
+// transactions of an address between two heights.
+//
+// The indexer follows the chain with sdk.BlockchainService.StreamBlocks. It
+// resumes from the last indexed block and, when the PreviousBlockHash of a
+// new block does not match the indexed one, it rolls back to the height
+// where the indexed chain and the node agree.
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/proximax-storage/nem2-sdk-go/sdk"
+	"go.etcd.io/bbolt"
+	"golang.org/x/net/context"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNilClient = errors.New("client should not be nil")
+	ErrNotFound  = errors.New("not found in the index")
+	// ErrForkTooDeep is returned when the indexed chain and the node do not
+	// agree on any block down to the first indexed one.
+	ErrForkTooDeep = errors.New("no common block with the node")
+)
+
+// errRollback ends a stream after a rollback, to restart it from the fork
+var errRollback = errors.New("rolled back")
+
+// Options configures an Indexer; zero values mean defaults.
+type Options struct {
+	// FromHeight is the first block indexed by an empty index, 1 by default.
+	FromHeight *big.Int
+	// Stream configures the streaming of the blocks. When Stream.ToHeight
+	// is set Run returns once it is indexed.
+	Stream sdk.StreamOptions
+	// OnBlock is called after a block is indexed.
+	OnBlock func(block *Block)
+	// OnRollback is called after the blocks above forkHeight were removed
+	// from the index, with the removed blocks, highest first.
+	OnRollback func(forkHeight *big.Int, orphaned []*Block)
+}
+
+// Indexer indexes blocks, transactions, the transactions of each address
+// and the mosaic balances of each address. Its query methods may be called
+// while Run is indexing.
+type Indexer struct {
+	client *sdk.Client
+	db     *bbolt.DB
+	opts   Options
+}
+
+// Open opens the index at path, creating it if needed.
+func Open(path string, client *sdk.Client, opts *Options) (*Indexer, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(createBuckets); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	ix := &Indexer{client: client, db: db}
+	if opts != nil {
+		ix.opts = *opts
+	}
+	if ix.opts.FromHeight == nil || ix.opts.FromHeight.Sign() <= 0 {
+		ix.opts.FromHeight = big.NewInt(1)
+	}
+
+	return ix, nil
+}
+
+// Close closes the index.
+func (ix *Indexer) Close() error {
+	return ix.db.Close()
+}
+
+// Run indexes the blocks following the last indexed one until the context
+// is done, an error occurs or Options.Stream.ToHeight is indexed.
+func (ix *Indexer) Run(ctx context.Context) error {
+	for {
+		height, err := ix.Height()
+		if err != nil {
+			return err
+		}
+
+		from := new(big.Int).Add(height, big.NewInt(1))
+		if height.Sign() == 0 {
+			from = ix.opts.FromHeight
+		}
+
+		if to := ix.opts.Stream.ToHeight; to != nil && from.Cmp(to) > 0 {
+			return nil
+		}
+
+		err = ix.follow(ctx, from)
+		if err != errRollback {
+			return err
+		}
+	}
+}
+
+// follow indexes the blocks streamed from height from on.
+func (ix *Indexer) follow(ctx context.Context, from *big.Int) error {
+	stream, err := ix.client.Blockchain.StreamBlocks(ctx, from, &ix.opts.Stream)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for block := range stream.Blocks {
+		if err := ix.index(ctx, block); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+// index adds the block to the index, or rolls the index back when the block
+// does not follow the last indexed one.
+func (ix *Indexer) index(ctx context.Context, block *sdk.StreamedBlock) error {
+	b := newBlock(block)
+	txs := make([]*Transaction, 0, len(block.Transactions))
+
+	for _, tx := range block.Transactions {
+		if t := newTransaction(tx); t != nil {
+			txs = append(txs, t)
+			b.Transactions = append(b.Transactions, t.Hash)
+		}
+	}
+
+	var forked bool
+
+	err := ix.db.Update(func(tx *bbolt.Tx) error {
+		prev, err := getBlock(tx, b.Height.Uint64()-1)
+		if err != nil {
+			return err
+		}
+
+		if prev != nil && !strings.EqualFold(prev.Hash, b.PreviousBlockHash) {
+			forked = true
+			return nil
+		}
+
+		return putBlock(tx, b, txs)
+	})
+	if err != nil {
+		return err
+	}
+
+	if forked {
+		if err := ix.rollback(ctx); err != nil {
+			return err
+		}
+		return errRollback
+	}
+
+	if ix.opts.OnBlock != nil {
+		ix.opts.OnBlock(b)
+	}
+
+	return nil
+}
+
+// rollback removes the indexed blocks down to the highest one the node
+// still has.
+func (ix *Indexer) rollback(ctx context.Context) error {
+	height, err := ix.Height()
+	if err != nil {
+		return err
+	}
+
+	fork := new(big.Int).Set(height)
+	for ; fork.Sign() > 0; fork.Sub(fork, big.NewInt(1)) {
+		indexed, err := ix.Block(fork)
+		if err == ErrNotFound {
+			// the first indexed block is passed
+			return ErrForkTooDeep
+		}
+		if err != nil {
+			return err
+		}
+
+		block, err := ix.client.Blockchain.GetBlockByHeight(ctx, fork)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(block.Hash, indexed.Hash) {
+			break
+		}
+	}
+
+	if fork.Sign() == 0 {
+		return ErrForkTooDeep
+	}
+
+	orphaned := make([]*Block, 0)
+
+	err = ix.db.Update(func(tx *bbolt.Tx) error {
+		for h := height.Uint64(); h > fork.Uint64(); h-- {
+			block, err := getBlock(tx, h)
+			if err != nil {
+				return err
+			}
+
+			if err := deleteBlock(tx, block); err != nil {
+				return err
+			}
+
+			orphaned = append(orphaned, block)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if ix.opts.OnRollback != nil && len(orphaned) > 0 {
+		ix.opts.OnRollback(fork, orphaned)
+	}
+
+	return nil
+}
+
+// Height returns the height of the last indexed block, 0 when the index is
+// empty.
+func (ix *Indexer) Height() (*big.Int, error) {
+	var height uint64
+
+	err := ix.db.View(func(tx *bbolt.Tx) error {
+		height = getHeight(tx)
+		return nil
+	})
+
+	return new(big.Int).SetUint64(height), err
+}
+
+// Block returns the indexed block at height.
+func (ix *Indexer) Block(height *big.Int) (*Block, error) {
+	if height == nil || height.Sign() == 0 {
+		return nil, sdk.ErrNilOrZeroHeight
+	}
+
+	var block *Block
+
+	err := ix.db.View(func(tx *bbolt.Tx) (err error) {
+		block, err = getBlock(tx, height.Uint64())
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, ErrNotFound
+	}
+
+	return block, nil
+}
+
+// Transaction returns the indexed transaction with the hash.
+func (ix *Indexer) Transaction(hash sdk.Hash) (*Transaction, error) {
+	var t *Transaction
+
+	err := ix.db.View(func(tx *bbolt.Tx) (err error) {
+		t, err = getTransaction(tx, hashKey(string(hash)))
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, ErrNotFound
+	}
+
+	return t, nil
+}
+
+// TransactionsByAddress returns the transactions signed by or sent to
+// address between the heights from and to, both included, in chain order.
+// A nil from or to leaves the range open.
+func (ix *Indexer) TransactionsByAddress(address *sdk.Address, from, to *big.Int) ([]*Transaction, error) {
+	if address == nil {
+		return nil, sdk.ErrNilAddress
+	}
+
+	lo, hi := uint64(0), ^uint64(0)
+	if from != nil {
+		lo = from.Uint64()
+	}
+	if to != nil {
+		hi = to.Uint64()
+	}
+
+	txs := make([]*Transaction, 0)
+
+	err := ix.db.View(func(tx *bbolt.Tx) error {
+		for _, hash := range addressTransactions(tx, rawAddress(address), lo, hi) {
+			t, err := getTransaction(tx, hash)
+			if err != nil {
+				return err
+			}
+
+			if t != nil {
+				txs = append(txs, t)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// Balances returns the balances of address, as changed by the indexed
+// transfers and supply changes, see Transaction.Changes. Mosaics with a zero
+// balance are left out.
+func (ix *Indexer) Balances(address *sdk.Address) ([]*Mosaic, error) {
+	if address == nil {
+		return nil, sdk.ErrNilAddress
+	}
+
+	prefix := []byte(rawAddress(address))
+	balances := make([]*Mosaic, 0)
+
+	err := ix.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(balancesBucket).Cursor()
+
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			amount, ok := new(big.Int).SetString(string(v), 10)
+			if !ok {
+				return fmt.Errorf("balance %q is not a number", v)
+			}
+
+			balances = append(balances, &Mosaic{binary.BigEndian.Uint64(k[len(prefix):]), amount})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"fmt"
+	"github.com/proximax-storage/nem2-sdk-go/sdk"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testSigner    = "321DE652C4D3362FC2DDF7800F6582F4A10CFEA134B81F8AB6E4BE78BBA4D18E"
+	testRecipient = "27F6BEF9A7F75E33AE2EB2EBA10EF1D6BEA4D30EBD5E39AF8EE06E96E11AE2A9"
+	testXemId     = uint64(3576016193)<<32 | 3646934825
+)
+
+// testBlock is a block of the test chain, whose hash is derived from its
+// height and fork.
+type testBlock struct {
+	height, fork int
+	txs          []string
+}
+
+func (b *testBlock) hash() string {
+	return fmt.Sprintf("%064X", b.fork<<16|b.height)
+}
+
+func (b *testBlock) json(prev string) string {
+	return fmt.Sprintf(`{
+	"meta": {"hash": "%s", "generationHash": "%064X", "totalFee": [0, 0], "numTransactions": %d},
+	"block": {
+		"signature": "%0128X",
+		"signer": "%s",
+		"version": 36867,
+		"type": 32835,
+		"height": [%d, 0],
+		"timestamp": [%d, 0],
+		"difficulty": [276447232, 23283],
+		"previousBlockHash": "%s",
+		"blockTransactionsHash": "%064X"
+	}
+}`, b.hash(), 0, len(b.txs), 0, testSigner, b.height, b.height*1000, prev, 0)
+}
+
+func testTransfer(t *testing.T, height int, hash, signer, recipient string, amount uint64) string {
+	address, err := sdk.NewAddressFromPublicKey(recipient, sdk.MijinTest)
+	assert.Nil(t, err)
+	encoded, err := address.Encoded()
+	assert.Nil(t, err)
+
+	return fmt.Sprintf(`{
+	"meta": {"height": [%d, 0], "hash": "%s", "merkleComponentHash": "%s", "index": 0, "id": "%024X"},
+	"transaction": {
+		"signature": "%0128X",
+		"signer": "%s",
+		"version": 36867,
+		"type": 16724,
+		"fee": [0, 0],
+		"deadline": [1, 0],
+		"recipient": "%s",
+		"message": {"type": 0, "payload": ""},
+		"mosaics": [{"id": [3646934825, 3576016193], "amount": [%d, 0]}]
+	}
+}`, height, hash, hash, height, 0, signer, encoded, amount)
+}
+
+// newTestChain serves the blocks of a chain. The routes of a mock are
+// static, so a fork of the chain is served by another mock.
+func newTestChain(blocks ...*testBlock) *mock.Mock {
+	m := mock.NewMock(0)
+
+	m.AddRouter(&mock.Router{
+		Path:     "/chain/height",
+		RespBody: fmt.Sprintf(`{"height": [%d, 0]}`, len(blocks)),
+	})
+
+	infos := make([]string, len(blocks))
+	for i, block := range blocks {
+		prev := fmt.Sprintf("%064X", 0)
+		if i > 0 {
+			prev = blocks[i-1].hash()
+		}
+		infos[i] = block.json(prev)
+
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf("/block/%d", block.height),
+			RespBody: infos[i],
+		})
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf("/block/%d/transactions", block.height),
+			RespBody: "[" + strings.Join(block.txs, ",") + "]",
+		})
+	}
+
+	// every page of blocks which can be requested
+	for from := 1; from <= len(blocks); from++ {
+		for limit := 1; limit <= len(blocks); limit++ {
+			to := from + limit - 1
+			if to > len(blocks) {
+				to = len(blocks)
+			}
+
+			m.AddRouter(&mock.Router{
+				Path:     fmt.Sprintf("/blocks/%d/limit/%d", from, limit),
+				RespBody: "[" + strings.Join(infos[from-1:to], ",") + "]",
+			})
+		}
+	}
+
+	return m
+}
+
+func testClient(t *testing.T, m *mock.Mock) *sdk.Client {
+	conf, err := sdk.NewConfig(m.GetServerURL(), sdk.MijinTest)
+	assert.Nil(t, err)
+
+	return sdk.NewClient(nil, conf)
+}
+
+func TestIndexer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "indexer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "index.db")
+
+	signer, err := sdk.NewAccountFromPublicKey(testSigner, sdk.MijinTest)
+	assert.Nil(t, err)
+	recipient, err := sdk.NewAccountFromPublicKey(testRecipient, sdk.MijinTest)
+	assert.Nil(t, err)
+
+	hash := func(n int) string {
+		return fmt.Sprintf("%064X", n)
+	}
+
+	chain := newTestChain(
+		&testBlock{height: 1},
+		&testBlock{height: 2, txs: []string{testTransfer(t, 2, hash(2), testSigner, testRecipient, 100)}},
+		&testBlock{height: 3, txs: []string{testTransfer(t, 3, hash(3), testRecipient, testSigner, 30)}},
+		&testBlock{height: 4},
+	)
+	defer chain.Close()
+
+	ix, err := Open(path, testClient(t, chain), &Options{Stream: sdk.StreamOptions{ToHeight: big.NewInt(4), PageSize: 3}})
+	assert.Nil(t, err)
+
+	assert.Nil(t, ix.Run(context.Background()))
+
+	height, err := ix.Height()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), height.Int64())
+
+	txs, err := ix.TransactionsByAddress(recipient.Address, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, sdk.Hash(hash(2)), txs[0].Hash)
+	assert.Equal(t, sdk.Hash(hash(3)), txs[1].Hash)
+
+	txs, err = ix.TransactionsByAddress(recipient.Address, big.NewInt(3), big.NewInt(4))
+	assert.Nil(t, err)
+	assert.Len(t, txs, 1)
+
+	balances, err := ix.Balances(recipient.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Mosaic{{testXemId, big.NewInt(70)}}, balances)
+
+	assert.Nil(t, ix.Close())
+
+	// the node switched to a fork from height 3 on; the index resumes and
+	// rolls back to height 2. The node returns the hash of the transaction of
+	// block 5 in lower case.
+	fork := newTestChain(
+		&testBlock{height: 1},
+		&testBlock{height: 2, txs: []string{testTransfer(t, 2, hash(2), testSigner, testRecipient, 100)}},
+		&testBlock{height: 3, fork: 1},
+		&testBlock{height: 4, fork: 1},
+		&testBlock{height: 5, fork: 1, txs: []string{testTransfer(t, 5, strings.ToLower(hash(0xAB5)), testSigner, testRecipient, 1)}},
+	)
+	defer fork.Close()
+
+	var (
+		forkHeight *big.Int
+		orphaned   []*Block
+	)
+
+	ix, err = Open(path, testClient(t, fork), &Options{
+		Stream: sdk.StreamOptions{ToHeight: big.NewInt(5)},
+		OnRollback: func(height *big.Int, blocks []*Block) {
+			forkHeight, orphaned = height, blocks
+		},
+	})
+	assert.Nil(t, err)
+	defer ix.Close()
+
+	assert.Nil(t, ix.Run(context.Background()))
+
+	assert.Equal(t, int64(2), forkHeight.Int64())
+	assert.Len(t, orphaned, 2)
+	assert.Equal(t, int64(4), orphaned[0].Height.Int64())
+
+	block, err := ix.Block(big.NewInt(3))
+	assert.Nil(t, err)
+	assert.Equal(t, (&testBlock{height: 3, fork: 1}).hash(), block.Hash)
+
+	_, err = ix.Transaction(sdk.Hash(hash(3)))
+	assert.Equal(t, ErrNotFound, err)
+
+	tx, err := ix.Transaction(sdk.Hash(hash(0xAB5)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), tx.Height.Int64())
+
+	txs, err = ix.TransactionsByAddress(recipient.Address, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 2)
+
+	balances, err = ix.Balances(recipient.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Mosaic{{testXemId, big.NewInt(101)}}, balances)
+
+	balances, err = ix.Balances(signer.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Mosaic{{testXemId, big.NewInt(-101)}}, balances)
+}
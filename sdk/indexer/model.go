@@ -0,0 +1,187 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"github.com/proximax-storage/nem2-sdk-go/sdk"
+	"math/big"
+	"strings"
+)
+
+// Block is an indexed block.
+type Block struct {
+	Height            *big.Int
+	Hash              string
+	PreviousBlockHash string
+	Timestamp         *big.Int
+	Signer            string
+	TotalFee          *big.Int
+	// Transactions are the hashes of the transactions of the block, in order.
+	Transactions []sdk.Hash
+}
+
+// Mosaic is an amount of a mosaic moved by a transaction.
+type Mosaic struct {
+	MosaicId uint64
+	Amount   *big.Int
+}
+
+// BalanceChange is the change of the balance of a mosaic of an account made
+// by a transaction; it is negative for debits.
+type BalanceChange struct {
+	Address  string
+	MosaicId uint64
+	Amount   *big.Int
+}
+
+// Transaction is an indexed transaction. The full transaction can be
+// requested with sdk.TransactionService.GetTransaction.
+type Transaction struct {
+	Hash   sdk.Hash
+	Height *big.Int
+	Index  uint32
+	Type   sdk.TransactionType
+	// Signer is the public key of the signer.
+	Signer    string
+	Recipient string
+	Message   string
+	Mosaics   []*Mosaic
+	// Inner are the transactions of an aggregate, without hash.
+	Inner []*Transaction
+	// Addresses are the raw addresses of the signers and recipients of the
+	// transaction and of its inner transactions.
+	Addresses []string
+	// Changes are the balance changes of transfers and supply changes.
+	// Transaction fees, harvesting and locked funds are not accounted.
+	Changes []*BalanceChange
+}
+
+func newBlock(block *sdk.StreamedBlock) *Block {
+	b := &Block{
+		Height:            block.Height,
+		Hash:              block.Hash,
+		PreviousBlockHash: block.PreviousBlockHash,
+		Timestamp:         block.Timestamp,
+		TotalFee:          block.TotalFee,
+		Transactions:      make([]sdk.Hash, 0, len(block.Transactions)),
+	}
+
+	if block.Signer != nil {
+		b.Signer = block.Signer.PublicKey
+	}
+
+	return b
+}
+
+// newTransaction returns the record of tx, or nil when tx is not confirmed.
+func newTransaction(tx sdk.Transaction) *Transaction {
+	atx := tx.GetAbstractTransaction()
+	if atx == nil || atx.TransactionInfo == nil {
+		return nil
+	}
+
+	t := newInnerTransaction(tx)
+	t.Hash = atx.TransactionInfo.Hash
+	t.Height = atx.TransactionInfo.Height
+	t.Index = atx.TransactionInfo.Index
+
+	seen := make(map[string]bool)
+	addresses := make([]string, 0, len(t.Addresses))
+
+	for _, inner := range append([]*Transaction{t}, t.Inner...) {
+		for _, address := range inner.Addresses {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	t.Addresses = addresses
+
+	for _, inner := range t.Inner {
+		t.Changes = append(t.Changes, inner.Changes...)
+	}
+
+	return t
+}
+
+func newInnerTransaction(tx sdk.Transaction) *Transaction {
+	atx := tx.GetAbstractTransaction()
+
+	t := &Transaction{
+		Type:      atx.Type,
+		Mosaics:   make([]*Mosaic, 0),
+		Addresses: make([]string, 0),
+		Changes:   make([]*BalanceChange, 0),
+	}
+
+	var signer string
+	if atx.Signer != nil {
+		t.Signer = atx.Signer.PublicKey
+
+		if atx.Signer.Address != nil {
+			signer = rawAddress(atx.Signer.Address)
+			t.Addresses = append(t.Addresses, signer)
+		}
+	}
+
+	switch tx := tx.(type) {
+	case *sdk.AggregateTransaction:
+		for _, inner := range tx.InnerTransactions {
+			t.Inner = append(t.Inner, newInnerTransaction(inner))
+		}
+
+	case *sdk.TransferTransaction:
+		if tx.Recipient != nil {
+			t.Recipient = rawAddress(tx.Recipient)
+			t.Addresses = append(t.Addresses, t.Recipient)
+		}
+
+		if tx.Message != nil {
+			t.Message = tx.Message.Payload
+		}
+
+		for _, m := range tx.Mosaics {
+			if m == nil || m.MosaicId == nil || m.Amount == nil {
+				continue
+			}
+
+			id := mosaicId(m.MosaicId)
+			t.Mosaics = append(t.Mosaics, &Mosaic{id, m.Amount})
+			t.change(signer, id, new(big.Int).Neg(m.Amount))
+			t.change(t.Recipient, id, m.Amount)
+		}
+
+	case *sdk.MosaicSupplyChangeTransaction:
+		if tx.MosaicId == nil || tx.Delta == nil {
+			break
+		}
+
+		id := mosaicId(tx.MosaicId)
+		t.Mosaics = append(t.Mosaics, &Mosaic{id, tx.Delta})
+
+		if tx.MosaicSupplyType == sdk.Increase {
+			t.change(signer, id, tx.Delta)
+		} else {
+			t.change(signer, id, new(big.Int).Neg(tx.Delta))
+		}
+	}
+
+	return t
+}
+
+func (t *Transaction) change(address string, mosaicId uint64, amount *big.Int) {
+	if address != "" {
+		t.Changes = append(t.Changes, &BalanceChange{address, mosaicId, amount})
+	}
+}
+
+func mosaicId(id *sdk.MosaicId) uint64 {
+	return (*big.Int)(id).Uint64()
+}
+
+func rawAddress(address *sdk.Address) string {
+	return strings.ToUpper(strings.Replace(address.Address, "-", "", -1))
+}
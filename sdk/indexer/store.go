@@ -0,0 +1,224 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"go.etcd.io/bbolt"
+	"math/big"
+	"strings"
+)
+
+// buckets of the store
+var (
+	// metaBucket holds the height of the last indexed block
+	metaBucket = []byte("meta")
+	// blocksBucket maps heights to blocks
+	blocksBucket = []byte("blocks")
+	// transactionsBucket maps hashes to transactions
+	transactionsBucket = []byte("transactions")
+	// addressesBucket maps address, height and index to the hash of the
+	// transactions of an address, in order
+	addressesBucket = []byte("addresses")
+	// balancesBucket maps address and mosaic id to the balance
+	balancesBucket = []byte("balances")
+
+	heightKey = []byte("height")
+)
+
+func createBuckets(tx *bbolt.Tx) error {
+	for _, name := range [][]byte{metaBucket, blocksBucket, transactionsBucket, addressesBucket, balancesBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func heightBytes(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func addressKey(address string, height uint64, index uint32) []byte {
+	b := make([]byte, len(address)+12)
+	copy(b, address)
+	binary.BigEndian.PutUint64(b[len(address):], height)
+	binary.BigEndian.PutUint32(b[len(address)+8:], index)
+	return b
+}
+
+// hashKey returns the key of a transaction hash. The REST API does not
+// guarantee the case of hashes, so they are stored in upper case.
+func hashKey(hash string) []byte {
+	return []byte(strings.ToUpper(hash))
+}
+
+func balanceKey(address string, mosaicId uint64) []byte {
+	return append([]byte(address), heightBytes(mosaicId)...)
+}
+
+func getHeight(tx *bbolt.Tx) uint64 {
+	b := tx.Bucket(metaBucket).Get(heightKey)
+	if b == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(b)
+}
+
+func getBlock(tx *bbolt.Tx, height uint64) (*Block, error) {
+	b := tx.Bucket(blocksBucket).Get(heightBytes(height))
+	if b == nil {
+		return nil, nil
+	}
+
+	block := &Block{}
+	if err := json.Unmarshal(b, block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func getTransaction(tx *bbolt.Tx, hash []byte) (*Transaction, error) {
+	b := tx.Bucket(transactionsBucket).Get(hash)
+	if b == nil {
+		return nil, nil
+	}
+
+	t := &Transaction{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func put(bucket *bbolt.Bucket, key []byte, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put(key, b)
+}
+
+// putBlock stores the block and its transactions, applies their balance
+// changes and makes the block the last indexed one.
+func putBlock(tx *bbolt.Tx, block *Block, txs []*Transaction) error {
+	height := block.Height.Uint64()
+
+	for _, t := range txs {
+		if err := put(tx.Bucket(transactionsBucket), hashKey(string(t.Hash)), t); err != nil {
+			return err
+		}
+
+		for _, address := range t.Addresses {
+			if err := tx.Bucket(addressesBucket).Put(addressKey(address, height, t.Index), hashKey(string(t.Hash))); err != nil {
+				return err
+			}
+		}
+
+		if err := applyChanges(tx, t.Changes, false); err != nil {
+			return err
+		}
+	}
+
+	if err := put(tx.Bucket(blocksBucket), heightBytes(height), block); err != nil {
+		return err
+	}
+
+	return tx.Bucket(metaBucket).Put(heightKey, heightBytes(height))
+}
+
+// deleteBlock removes the last indexed block and its transactions and
+// reverts their balance changes.
+func deleteBlock(tx *bbolt.Tx, block *Block) error {
+	height := block.Height.Uint64()
+
+	for _, hash := range block.Transactions {
+		t, err := getTransaction(tx, hashKey(string(hash)))
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			continue
+		}
+
+		for _, address := range t.Addresses {
+			if err := tx.Bucket(addressesBucket).Delete(addressKey(address, height, t.Index)); err != nil {
+				return err
+			}
+		}
+
+		if err := applyChanges(tx, t.Changes, true); err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(transactionsBucket).Delete(hashKey(string(hash))); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Bucket(blocksBucket).Delete(heightBytes(height)); err != nil {
+		return err
+	}
+
+	return tx.Bucket(metaBucket).Put(heightKey, heightBytes(height-1))
+}
+
+func applyChanges(tx *bbolt.Tx, changes []*BalanceChange, revert bool) error {
+	bucket := tx.Bucket(balancesBucket)
+
+	for _, c := range changes {
+		key := balanceKey(c.Address, c.MosaicId)
+
+		balance := big.NewInt(0)
+		if b := bucket.Get(key); b != nil {
+			if _, ok := balance.SetString(string(b), 10); !ok {
+				return fmt.Errorf("balance %q is not a number", b)
+			}
+		}
+
+		if revert {
+			balance.Sub(balance, c.Amount)
+		} else {
+			balance.Add(balance, c.Amount)
+		}
+
+		var err error
+		if balance.Sign() == 0 {
+			err = bucket.Delete(key)
+		} else {
+			err = bucket.Put(key, []byte(balance.String()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addressTransactions returns the hashes of the transactions of address
+// between the heights from and to, both included. The hashes are copied, so
+// they remain valid after tx.
+func addressTransactions(tx *bbolt.Tx, address string, from, to uint64) [][]byte {
+	hashes := make([][]byte, 0)
+	end := addressKey(address, to, ^uint32(0))
+
+	c := tx.Bucket(addressesBucket).Cursor()
+	for k, v := c.Seek(addressKey(address, from, 0)); k != nil && bytes.Compare(k, end) <= 0; k, v = c.Next() {
+		hashes = append(hashes, append([]byte(nil), v...))
+	}
+
+	return hashes
+}
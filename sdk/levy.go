@@ -0,0 +1,226 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/str"
+	"golang.org/x/net/context"
+	"math/big"
+)
+
+// MosaicLevyType is the way the levy of a mosaic is calculated:
+// a fixed amount per transfer - LevyAbsolute.
+// a part of the transferred amount - LevyPercentile.
+type MosaicLevyType uint8
+
+// MosaicLevyType values
+const (
+	LevyAbsolute MosaicLevyType = iota + 1
+	LevyPercentile
+)
+
+func (t MosaicLevyType) String() string {
+	return fmt.Sprintf("%d", t)
+}
+
+// LevyPercentileDivisor is the divisor of the fee of a LevyPercentile levy,
+// which is expressed in hundredths of a percent of the transferred amount.
+const LevyPercentileDivisor = 10000
+
+// MosaicLevy is the fee paid to Recipient, in mosaic MosaicId, every time the
+// levied mosaic is transferred.
+type MosaicLevy struct {
+	Type      MosaicLevyType
+	Recipient *Address
+	MosaicId  *MosaicId
+	Fee       *big.Int
+}
+
+// NewMosaicLevy returns a MosaicLevy. For LevyAbsolute fee is the amount paid
+// per transfer, for LevyPercentile it is in units of 1/LevyPercentileDivisor
+// of the transferred amount.
+func NewMosaicLevy(levyType MosaicLevyType, recipient *Address, mosaicId *MosaicId, fee *big.Int) (*MosaicLevy, error) {
+	if levyType != LevyAbsolute && levyType != LevyPercentile {
+		return nil, ErrInvalidLevyType
+	}
+
+	if recipient == nil {
+		return nil, ErrNilAddress
+	}
+
+	if mosaicId == nil {
+		return nil, ErrNilMosaicId
+	}
+
+	if fee == nil || fee.Sign() < 0 {
+		return nil, ErrInvalidLevyFee
+	}
+
+	return &MosaicLevy{
+		Type:      levyType,
+		Recipient: recipient,
+		MosaicId:  mosaicId,
+		Fee:       fee,
+	}, nil
+}
+
+func (l *MosaicLevy) String() string {
+	return str.StructToString(
+		"MosaicLevy",
+		str.NewField("Type", str.IntPattern, l.Type),
+		str.NewField("Recipient", str.StringPattern, l.Recipient),
+		str.NewField("MosaicId", str.StringPattern, l.MosaicId),
+		str.NewField("Fee", str.StringPattern, l.Fee),
+	)
+}
+
+// Calculate returns the levy due on transferring amount of the levied mosaic.
+// Percentile levies are rounded down.
+func (l *MosaicLevy) Calculate(amount *big.Int) *big.Int {
+	if amount == nil || amount.Sign() == 0 {
+		return big.NewInt(0)
+	}
+
+	if l.Type == LevyAbsolute {
+		return new(big.Int).Set(l.Fee)
+	}
+
+	levy := new(big.Int).Mul(amount, l.Fee)
+	return levy.Quo(levy, big.NewInt(LevyPercentileDivisor))
+}
+
+// CalculateLevies returns the levies due on transferring mosaics, summed per
+// levy mosaic, in the order the levy mosaics first appear. mscInfos should
+// contain the MosaicInfo of every transferred mosaic, as returned by
+// MosaicService.GetMosaics; otherwise ErrMissingMosaicInfo is returned.
+func CalculateLevies(mosaics []*Mosaic, mscInfos []*MosaicInfo) ([]*Mosaic, error) {
+	levies := make(map[string]*MosaicLevy, len(mscInfos))
+	for _, mscInfo := range mscInfos {
+		if mscInfo != nil && mscInfo.MosaicId != nil {
+			levies[mscInfo.MosaicId.String()] = mscInfo.Levy
+		}
+	}
+
+	due := newMosaicSum()
+
+	for _, m := range mosaics {
+		if m == nil || m.MosaicId == nil {
+			return nil, ErrNilMosaicId
+		}
+
+		levy, ok := levies[m.MosaicId.String()]
+		if !ok {
+			return nil, ErrMissingMosaicInfo
+		}
+
+		if levy != nil {
+			due.add(levy.MosaicId, levy.Calculate(m.Amount))
+		}
+	}
+
+	return due.mosaics(), nil
+}
+
+// CalculateTransferCost returns the total amount of each mosaic a transfer of
+// mosaics costs its signer: the transferred mosaics plus their levies, see
+// CalculateLevies. The transaction fee is not included.
+func CalculateTransferCost(mosaics []*Mosaic, mscInfos []*MosaicInfo) ([]*Mosaic, error) {
+	levies, err := CalculateLevies(mosaics, mscInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := newMosaicSum()
+	for _, m := range mosaics {
+		cost.add(m.MosaicId, m.Amount)
+	}
+	for _, m := range levies {
+		cost.add(m.MosaicId, m.Amount)
+	}
+
+	return cost.mosaics(), nil
+}
+
+// Levies returns the levies due on the mosaics of the transfer, see
+// CalculateLevies.
+func (tx *TransferTransaction) Levies(mscInfos []*MosaicInfo) ([]*Mosaic, error) {
+	return CalculateLevies(tx.Mosaics, mscInfos)
+}
+
+// TotalCost returns the total amount of each mosaic the transfer costs its
+// signer: the transferred mosaics, their levies and the transaction fee in xem.
+func (tx *TransferTransaction) TotalCost(mscInfos []*MosaicInfo) ([]*Mosaic, error) {
+	cost, err := CalculateTransferCost(tx.Mosaics, mscInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Fee == nil || tx.Fee.Sign() == 0 {
+		return cost, nil
+	}
+
+	sum := newMosaicSum()
+	for _, m := range cost {
+		sum.add(m.MosaicId, m.Amount)
+	}
+	sum.add(XemMosaicId, tx.Fee)
+
+	return sum.mosaics(), nil
+}
+
+// GetTransferCost returns the total amount of each mosaic transferring
+// mosaics costs, levies included, fetching the levies with GetMosaics.
+func (ref *MosaicService) GetTransferCost(ctx context.Context, mosaics []*Mosaic) ([]*Mosaic, error) {
+	mscIds := make([]*MosaicId, 0, len(mosaics))
+	for _, m := range mosaics {
+		if m == nil || m.MosaicId == nil {
+			return nil, ErrNilMosaicId
+		}
+
+		mscIds = append(mscIds, m.MosaicId)
+	}
+
+	mscInfos, err := ref.GetMosaics(ctx, mscIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return CalculateTransferCost(mosaics, mscInfos)
+}
+
+// mosaicSum adds up amounts per mosaic, keeping the order mosaics are added in.
+type mosaicSum struct {
+	order   []*MosaicId
+	amounts map[string]*big.Int
+}
+
+func newMosaicSum() *mosaicSum {
+	return &mosaicSum{amounts: make(map[string]*big.Int)}
+}
+
+func (s *mosaicSum) add(mosaicId *MosaicId, amount *big.Int) {
+	if amount == nil {
+		return
+	}
+
+	sum, ok := s.amounts[mosaicId.String()]
+	if !ok {
+		sum = big.NewInt(0)
+		s.amounts[mosaicId.String()] = sum
+		s.order = append(s.order, mosaicId)
+	}
+
+	sum.Add(sum, amount)
+}
+
+func (s *mosaicSum) mosaics() []*Mosaic {
+	mosaics := make([]*Mosaic, 0, len(s.order))
+	for _, mosaicId := range s.order {
+		mosaics = append(mosaics, &Mosaic{mosaicId, s.amounts[mosaicId.String()]})
+	}
+
+	return mosaics
+}
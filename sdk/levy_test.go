@@ -0,0 +1,95 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+var levyRecipient = NewAddress("TBFBW6TUGLEWQIBCMTBMXXQORZKUP3WTVVPAYGJN", MijinTest)
+
+func TestNewMosaicLevy(t *testing.T) {
+	_, err := NewMosaicLevy(0, levyRecipient, XemMosaicId, big.NewInt(1))
+	assert.Equal(t, ErrInvalidLevyType, err)
+
+	_, err = NewMosaicLevy(LevyAbsolute, nil, XemMosaicId, big.NewInt(1))
+	assert.Equal(t, ErrNilAddress, err)
+
+	_, err = NewMosaicLevy(LevyPercentile, levyRecipient, XemMosaicId, big.NewInt(-1))
+	assert.Equal(t, ErrInvalidLevyFee, err)
+
+	levy, err := NewMosaicLevy(LevyPercentile, levyRecipient, XemMosaicId, big.NewInt(250))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(25), levy.Calculate(big.NewInt(1000)).Int64())
+	assert.Equal(t, int64(0), levy.Calculate(big.NewInt(39)).Int64())
+
+	levy.Type = LevyAbsolute
+	assert.Equal(t, int64(250), levy.Calculate(big.NewInt(1)).Int64())
+	assert.Equal(t, int64(0), levy.Calculate(big.NewInt(0)).Int64())
+}
+
+func TestMosaicLevyDTO_toStruct(t *testing.T) {
+	dto := &mosaicLevyDTO{}
+	assert.Nil(t, json.Unmarshal([]byte(`{}`), dto))
+	levy, err := dto.toStruct()
+	assert.Nil(t, err)
+	assert.Nil(t, levy)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"type": 2,
+		"recipient": "984A1B7A7432C968202264C2CBDE0E8E5547EED3AD5E0C192D",
+		"mosaicId": [3646934825, 3576016193],
+		"fee": [100, 0]
+	}`), dto))
+	levy, err = dto.toStruct()
+	assert.Nil(t, err)
+	assert.Equal(t, LevyPercentile, levy.Type)
+	assert.Equal(t, levyRecipient.Address, levy.Recipient.Address)
+	assert.Equal(t, XemMosaicId.String(), levy.MosaicId.String())
+	assert.Equal(t, int64(100), levy.Fee.Int64())
+}
+
+func TestTransferTransaction_TotalCost(t *testing.T) {
+	token := bigIntToMosaicId(big.NewInt(100))
+	plain := bigIntToMosaicId(big.NewInt(200))
+
+	mscInfos := []*MosaicInfo{
+		{MosaicId: token, Levy: &MosaicLevy{LevyPercentile, levyRecipient, XemMosaicId, big.NewInt(100)}},
+		{MosaicId: plain},
+	}
+
+	tx := &TransferTransaction{
+		AbstractTransaction: AbstractTransaction{Fee: big.NewInt(5)},
+		Mosaics: []*Mosaic{
+			{token, big.NewInt(5000)},
+			{plain, big.NewInt(7)},
+			{XemMosaicId, big.NewInt(10)},
+		},
+	}
+
+	_, err := tx.Levies(mscInfos)
+	assert.Equal(t, ErrMissingMosaicInfo, err)
+
+	mscInfos = append(mscInfos, &MosaicInfo{MosaicId: XemMosaicId})
+
+	levies, err := tx.Levies(mscInfos)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{XemMosaicId.String() + " 50"}, mosaicAmounts(levies))
+
+	cost, err := tx.TotalCost(mscInfos)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"100 5000", "200 7", XemMosaicId.String() + " 65"}, mosaicAmounts(cost))
+}
+
+func mosaicAmounts(mosaics []*Mosaic) []string {
+	amounts := make([]string, len(mosaics))
+	for i, m := range mosaics {
+		amounts[i] = m.MosaicId.String() + " " + m.Amount.String()
+	}
+
+	return amounts
+}
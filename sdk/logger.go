@@ -0,0 +1,36 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+// Logger receives leveled, structured events from the SDK: REST requests,
+// retries, websocket reconnects, subscription changes and decode failures.
+// keyvals are alternating keys and values, as in log/slog, so a *slog.Logger
+// can be used as a Logger directly.
+// The SDK never writes to stdout itself; by default events are discarded.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// NopLogger returns a Logger which discards every event.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+func loggerOrNop(l Logger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+	return l
+}
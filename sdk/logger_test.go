@@ -0,0 +1,150 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level   string
+	msg     string
+	keyvals []interface{}
+}
+
+func (e logEntry) value(key string) interface{} {
+	for i := 0; i+1 < len(e.keyvals); i += 2 {
+		if e.keyvals[i] == key {
+			return e.keyvals[i+1]
+		}
+	}
+	return nil
+}
+
+type recordLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordLogger) add(level, msg string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, logEntry{level, msg, keyvals})
+}
+
+func (l *recordLogger) Debug(msg string, keyvals ...interface{}) { l.add("debug", msg, keyvals) }
+func (l *recordLogger) Info(msg string, keyvals ...interface{})  { l.add("info", msg, keyvals) }
+func (l *recordLogger) Warn(msg string, keyvals ...interface{})  { l.add("warn", msg, keyvals) }
+func (l *recordLogger) Error(msg string, keyvals ...interface{}) { l.add("error", msg, keyvals) }
+
+func (l *recordLogger) find(msg string) (logEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range l.entries {
+		if e.msg == msg {
+			return e, true
+		}
+	}
+	return logEntry{}, false
+}
+
+func (l *recordLogger) wait(msg string) (logEntry, bool) {
+	timeout := time.After(wsWait)
+	for {
+		if e, ok := l.find(msg); ok {
+			return e, true
+		}
+
+		select {
+		case <-timeout:
+			return logEntry{}, false
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestClient_Logger(t *testing.T) {
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(transactionRoute, transactionHash),
+		RespBody: transactionJson,
+	})
+	defer m.Close()
+
+	log := &recordLogger{}
+	client := m.getTestNetClientUnsafe()
+	client.SetLogger(log)
+
+	_, err := client.Transaction.GetTransaction(ctx, transactionHash)
+	assert.Nilf(t, err, "TransactionService.GetTransaction returned error: %s", err)
+
+	req, ok := log.find("rest request")
+	assert.True(t, ok)
+	assert.Equal(t, "debug", req.level)
+	assert.Equal(t, "GET", req.value("method"))
+
+	resp, ok := log.find("rest response")
+	assert.True(t, ok)
+	assert.Equal(t, 200, resp.value("status"))
+
+	_, ok = log.find("rest request failed")
+	assert.False(t, ok)
+
+	client.SetLogger(nil)
+	_, err = client.Transaction.GetTransaction(ctx, transactionHash)
+	assert.Nil(t, err)
+}
+
+func TestNewClient_NilConfig(t *testing.T) {
+	client := NewClient(nil, nil)
+
+	assert.NotNil(t, client)
+	assert.Equal(t, nopLogger{}, client.log)
+}
+
+func TestClientWebsocket_Logger(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	log := &recordLogger{}
+	ws.SetLogger(log)
+
+	sub, err := ws.Subscribe.Block()
+	assert.Nilf(t, err, "SubscribeService.Block returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathBlock, wsWait))
+
+	e, ok := log.find("websocket subscribed")
+	assert.True(t, ok)
+	assert.Equal(t, pathBlock, e.value("subscribe"))
+
+	srv.DropConnections()
+	assert.Nil(t, srv.WaitSubscribed(pathBlock, wsWait))
+
+	e, ok = log.wait("websocket reconnecting")
+	assert.True(t, ok)
+	assert.Equal(t, "warn", e.level)
+
+	e, ok = log.wait("websocket reconnected")
+	assert.True(t, ok)
+	assert.Equal(t, srv.Subscribers(pathBlock)[0], e.value("uid"))
+
+	_, err = srv.PublishBlock([]byte(`{"block": {`))
+	assert.Nil(t, err)
+
+	e, ok = log.wait("websocket message decode failed")
+	assert.True(t, ok)
+	assert.Equal(t, "error", e.level)
+
+	assert.Nil(t, sub.Unsubscribe())
+
+	_, ok = log.find("websocket unsubscribed")
+	assert.True(t, ok)
+}
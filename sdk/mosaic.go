@@ -15,6 +15,31 @@ import (
 // MosaicService provides a set of methods for obtaining information about the mosaics
 type MosaicService service
 
+// GetMosaicDivisibility returns the divisibility of the mosaic.
+// It is fetched with GetMosaic once and then cached by the client, because
+// mosaic properties never change.
+func (ref *MosaicService) GetMosaicDivisibility(ctx context.Context, mosaicId *MosaicId) (int64, error) {
+	if mosaicId == nil {
+		return 0, ErrNilMosaicId
+	}
+
+	if d, ok := ref.client.divisibilities.get(mosaicId); ok {
+		return d, nil
+	}
+
+	mscInfo, err := ref.GetMosaic(ctx, mosaicId)
+	if err != nil {
+		return 0, err
+	}
+
+	if mscInfo.Properties == nil {
+		return 0, ErrNilMosaicProperties
+	}
+
+	ref.client.divisibilities.set(mosaicId, mscInfo.Properties.Divisibility)
+	return mscInfo.Properties.Divisibility, nil
+}
+
 // GetMosaic returns
 // @get /mosaic/{mosaicId}
 func (ref *MosaicService) GetMosaic(ctx context.Context, mosaicId *MosaicId) (*MosaicInfo, error) {
@@ -22,6 +47,17 @@ func (ref *MosaicService) GetMosaic(ctx context.Context, mosaicId *MosaicId) (*M
 		return nil, ErrNilMosaicId
 	}
 
+	mscInfo, err := ref.client.config.Cache.do(ctx, metadataKey{mosaicInfoKind, mosaicId.String()}, func(ctx context.Context) (interface{}, error) {
+		return ref.getMosaic(ctx, mosaicId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mscInfo.(*MosaicInfo), nil
+}
+
+func (ref *MosaicService) getMosaic(ctx context.Context, mosaicId *MosaicId) (*MosaicInfo, error) {
 	url := net.NewUrl(fmt.Sprintf(mosaicRoute, mosaicId.toHexString()))
 
 	dto := &mosaicInfoDTO{}
@@ -47,34 +83,119 @@ func (ref *MosaicService) GetMosaic(ctx context.Context, mosaicId *MosaicId) (*M
 	return mscInfo, nil
 }
 
-// GetMosaics get list mosaics Info
+// GetMosaics get list mosaics Info, in the order of mscIds, followed by the
+// mosaics returned for none of mscIds
 // post @/mosaic/
+// Long lists are requested in chunks, see BatchConfig; if only some ids
+// fail, the other mosaics are returned along with a *BatchError.
+// Mosaics found in Config.Cache are not requested.
 func (ref *MosaicService) GetMosaics(ctx context.Context, mscIds []*MosaicId) ([]*MosaicInfo, error) {
 	if len(mscIds) == 0 {
 		return nil, ErrEmptyMosaicIds
 	}
 
-	dtos := mosaicInfoDTOs(make([]*mosaicInfoDTO, 0))
+	var (
+		cache   = ref.client.config.Cache
+		found   = make([]*MosaicInfo, len(mscIds))
+		ids     = make([]string, 0, len(mscIds))
+		missing = make([]*MosaicId, 0, len(mscIds))
+		pos     = make([]int, 0, len(mscIds))
+	)
+
+	for i, mscId := range mscIds {
+		if mscId == nil {
+			return nil, ErrNilMosaicId
+		}
 
-	resp, err := ref.client.DoNewRequest(ctx, http.MethodPost, mosaicsRoute, &mosaicIds{mscIds}, &dtos)
-	if err != nil {
-		return nil, err
+		if v, ok := cache.get(metadataKey{mosaicInfoKind, mscId.String()}); ok {
+			found[i] = v.(*MosaicInfo)
+			continue
+		}
+
+		ids = append(ids, mscId.String())
+		missing = append(missing, mscId)
+		pos = append(pos, i)
 	}
 
-	if err = handleResponseStatusCode(resp, map[int]error{400: ErrInvalidRequest, 409: ErrArgumentNotValid}); err != nil {
+	var (
+		fetched   = make([]*MosaicInfo, len(ids))
+		unmatched = make([][]*MosaicInfo, len(ids))
+	)
+
+	err := ref.client.doBatch(ctx, ids, func(ctx context.Context, offset int, ids []string) error {
+		mscInfos, err := ref.getMosaics(ctx, missing[offset:offset+len(ids)])
+		if err != nil {
+			return err
+		}
+
+		keys := make([][]string, len(mscInfos))
+		for j, mscInfo := range mscInfos {
+			if mscInfo.MosaicId != nil {
+				keys[j] = []string{mscInfo.MosaicId.String()}
+			}
+		}
+
+		byId, others := batchMatch(ids, keys)
+		for i, j := range byId {
+			if j >= 0 {
+				fetched[offset+i] = mscInfos[j]
+			}
+		}
+		for _, j := range others {
+			unmatched[offset] = append(unmatched[offset], mscInfos[j])
+		}
+		return nil
+	})
+	if batchFailed(err) {
 		return nil, err
 	}
 
-	mscInfos, err := dtos.toStruct(ref.client.config.NetworkType)
+	for i, mscInfo := range fetched {
+		if mscInfo == nil {
+			continue
+		}
+
+		if hErr := ref.buildMosaicHierarchy(ctx, mscInfo); hErr != nil {
+			return nil, hErr
+		}
+
+		cache.set(metadataKey{mosaicInfoKind, ids[i]}, mscInfo)
+		found[pos[i]] = mscInfo
+	}
+
+	mscInfos := make([]*MosaicInfo, 0, len(found))
+	for _, mscInfo := range found {
+		if mscInfo != nil {
+			mscInfos = append(mscInfos, mscInfo)
+		}
+	}
+
+	for _, chunk := range unmatched {
+		for _, mscInfo := range chunk {
+			if hErr := ref.buildMosaicHierarchy(ctx, mscInfo); hErr != nil {
+				return nil, hErr
+			}
+
+			mscInfos = append(mscInfos, mscInfo)
+		}
+	}
+
+	return mscInfos, err
+}
+
+func (ref *MosaicService) getMosaics(ctx context.Context, mscIds []*MosaicId) ([]*MosaicInfo, error) {
+	dtos := mosaicInfoDTOs(make([]*mosaicInfoDTO, 0))
+
+	resp, err := ref.client.DoNewRequest(ctx, http.MethodPost, mosaicsRoute, &mosaicIds{mscIds}, &dtos)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = ref.buildMosaicsHierarchy(ctx, mscInfos); err != nil {
+	if err = handleResponseStatusCode(resp, map[int]error{400: ErrInvalidRequest, 409: ErrArgumentNotValid}); err != nil {
 		return nil, err
 	}
 
-	return mscInfos, nil
+	return dtos.toStruct(ref.client.config.NetworkType)
 }
 
 // GetMosaicsFromNamespaceUpToMosaic get mosaics information according to namespace ID & mosaic ID
@@ -122,13 +243,63 @@ func (ref *MosaicService) GetMosaicsFromNamespace(ctx context.Context, namespace
 	return ref.GetMosaicsFromNamespaceUpToMosaic(ctx, namespaceId, nil, pageSize)
 }
 
-// GetMosaicNames Get readable names for a set of mosaics
+// GetMosaicNames Get readable names for a set of mosaics, in the order of mscIds
 // post @/mosaic/names
+// Names found in Config.Cache are not requested.
 func (ref *MosaicService) GetMosaicNames(ctx context.Context, mscIds []*MosaicId) ([]*MosaicName, error) {
 	if len(mscIds) == 0 {
 		return nil, ErrEmptyMosaicIds
 	}
 
+	var (
+		cache   = ref.client.config.Cache
+		found   = make([]*MosaicName, len(mscIds))
+		missing = make([]*MosaicId, 0, len(mscIds))
+	)
+
+	for i, mscId := range mscIds {
+		if mscId == nil {
+			return nil, ErrNilMosaicId
+		}
+
+		if v, ok := cache.get(metadataKey{mosaicNameKind, mscId.String()}); ok {
+			found[i] = v.(*MosaicName)
+			continue
+		}
+
+		missing = append(missing, mscId)
+	}
+
+	if len(missing) > 0 {
+		mscNames, err := ref.getMosaicNames(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		byId := make(map[string]*MosaicName, len(mscNames))
+		for _, mscName := range mscNames {
+			byId[mscName.MosaicId.String()] = mscName
+			cache.set(metadataKey{mosaicNameKind, mscName.MosaicId.String()}, mscName)
+		}
+
+		for i, mscId := range mscIds {
+			if found[i] == nil {
+				found[i] = byId[mscId.String()]
+			}
+		}
+	}
+
+	mscNames := make([]*MosaicName, 0, len(found))
+	for _, mscName := range found {
+		if mscName != nil {
+			mscNames = append(mscNames, mscName)
+		}
+	}
+
+	return mscNames, nil
+}
+
+func (ref *MosaicService) getMosaicNames(ctx context.Context, mscIds []*MosaicId) ([]*MosaicName, error) {
 	dtos := mosaicNameDTOs(make([]*mosaicNameDTO, 0))
 
 	resp, err := ref.client.DoNewRequest(ctx, http.MethodPost, mosaicNamesRoute, &mosaicIds{mscIds}, &dtos)
@@ -143,6 +314,8 @@ func (ref *MosaicService) GetMosaicNames(ctx context.Context, mscIds []*MosaicId
 	return dtos.toStruct()
 }
 
+// buildMosaicHierarchy links mscInfo, which must not be cached yet, to its
+// namespace, whose hierarchy GetNamespace builds.
 func (ref *MosaicService) buildMosaicHierarchy(ctx context.Context, mscInfo *MosaicInfo) error {
 	if mscInfo == nil || mscInfo.Namespace == nil {
 		return nil
@@ -159,7 +332,7 @@ func (ref *MosaicService) buildMosaicHierarchy(ctx context.Context, mscInfo *Mos
 
 	mscInfo.Namespace = nsInfo
 
-	return ref.client.Namespace.buildNamespaceHierarchy(ctx, nsInfo)
+	return nil
 }
 
 func (ref *MosaicService) buildMosaicsHierarchy(ctx context.Context, mscInfos []*MosaicInfo) error {
@@ -7,6 +7,7 @@ package sdk
 import (
 	"errors"
 	"math/big"
+	"sync"
 )
 
 func bigIntToMosaicId(bigInt *big.Int) *MosaicId {
@@ -123,7 +124,34 @@ type mosaicDefinitionDTO struct {
 	Height      uint64DTO
 	Owner       string
 	Properties  mosaicPropertiesDTO
-	Levy        interface{}
+	Levy        *mosaicLevyDTO
+}
+
+// mosaicLevyDTO is temporary struct for reading the levy of a mosaic; mosaics
+// without levy have an empty one
+type mosaicLevyDTO struct {
+	Type      MosaicLevyType `json:"type"`
+	Recipient string         `json:"recipient"`
+	MosaicId  uint64DTO      `json:"mosaicId"`
+	Fee       uint64DTO      `json:"fee"`
+}
+
+func (dto *mosaicLevyDTO) toStruct() (*MosaicLevy, error) {
+	if dto == nil || dto.Type == 0 {
+		return nil, nil
+	}
+
+	recipient, err := NewAddressFromEncoded(dto.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	mosaicId, err := NewMosaicId(dto.MosaicId.toBigInt())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMosaicLevy(dto.Type, recipient, mosaicId, dto.Fee.toBigInt())
 }
 
 // mosaicInfoDTO is temporary struct for reading response & fill MosaicInfo
@@ -180,6 +208,10 @@ func (ref *mosaicInfoDTO) toStruct(networkType NetworkType) (*MosaicInfo, error)
 		mscInfo.Namespace = &NamespaceInfo{NamespaceId: nsId}
 	}
 
+	if mscInfo.Levy, err = ref.Mosaic.Levy.toStruct(); err != nil {
+		return nil, err
+	}
+
 	return mscInfo, nil
 }
 
@@ -221,3 +253,26 @@ func (ref *mosaicIds) MarshalJSON() ([]byte, error) {
 
 	return buf, nil
 }
+
+type divisibilityCache struct {
+	sync.RWMutex
+	m map[string]int64
+}
+
+func (c *divisibilityCache) get(mosaicId *MosaicId) (int64, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	d, ok := c.m[mosaicId.String()]
+	return d, ok
+}
+
+func (c *divisibilityCache) set(mosaicId *MosaicId, divisibility int64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.m == nil {
+		c.m = make(map[string]int64)
+	}
+	c.m[mosaicId.String()] = divisibility
+}
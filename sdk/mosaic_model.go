@@ -93,6 +93,7 @@ type MosaicInfo struct {
 	Height     *big.Int
 	Owner      *PublicAccount
 	Properties *MosaicProperties
+	Levy       *MosaicLevy /* Optional MosaicLevy may be nil */
 }
 
 func (m *MosaicInfo) String() string {
@@ -108,6 +109,7 @@ func (m *MosaicInfo) String() string {
 		str.NewField("Height", str.StringPattern, m.Height),
 		str.NewField("Owner", str.StringPattern, m.Owner),
 		str.NewField("Properties", str.StringPattern, m.Properties),
+		str.NewField("Levy", str.StringPattern, m.Levy),
 	)
 }
 
@@ -191,7 +193,11 @@ func Xem(amount int64) *Mosaic {
 	return &Mosaic{XemMosaicId, big.NewInt(amount)}
 }
 
+// XemDivisibility is the number of decimal places of xem
+const XemDivisibility = 6
+
 // XemRelative create relative xem with using xem as unit
 func XemRelative(amount int64) *Mosaic {
-	return Xem(big.NewInt(0).Mul(big.NewInt(1000000), big.NewInt(amount)).Int64())
+	unit := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(XemDivisibility), nil)
+	return &Mosaic{XemMosaicId, big.NewInt(0).Mul(unit, big.NewInt(amount))}
 }
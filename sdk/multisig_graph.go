@@ -0,0 +1,452 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// MultisigGraph is a navigable view of nested multisig accounts.
+// Accounts are identified by their public keys, which are compared
+// case insensitively. Edges go from a multisig account to its cosignatories.
+//
+// Catapult does not allow cycles, but a graph merged from several responses
+// may still contain them, so every traversal stops at accounts it has
+// already visited; use Cycles to detect them.
+type MultisigGraph struct {
+	accounts  map[string]*PublicAccount
+	multisigs map[string]*MultisigAccountInfo
+	cosigners map[string][]string // multisig -> cosignatories
+	parents   map[string][]string // cosignatory -> multisigs
+}
+
+// NewMultisigGraph builds a graph from multisig account infos, e.g. all the
+// levels of one or more MultisigAccountGraphInfo.
+func NewMultisigGraph(infos ...*MultisigAccountInfo) *MultisigGraph {
+	g := &MultisigGraph{
+		accounts:  make(map[string]*PublicAccount),
+		multisigs: make(map[string]*MultisigAccountInfo),
+		cosigners: make(map[string][]string),
+		parents:   make(map[string][]string),
+	}
+
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+
+		key := g.addAccount(&info.Account)
+		if len(info.Cosignatories) > 0 {
+			g.multisigs[key] = info
+		}
+
+		for _, c := range info.Cosignatories {
+			g.addEdge(key, g.addAccount(c))
+		}
+		for _, m := range info.MultisigAccounts {
+			g.addEdge(g.addAccount(m), key)
+		}
+	}
+
+	for _, keys := range g.cosigners {
+		sort.Strings(keys)
+	}
+	for _, keys := range g.parents {
+		sort.Strings(keys)
+	}
+
+	return g
+}
+
+// Graph returns the navigable graph of every level of the info.
+func (ref *MultisigAccountGraphInfo) Graph() *MultisigGraph {
+	infos := make([]*MultisigAccountInfo, 0)
+	for _, level := range sortedLevels(ref.MultisigAccounts) {
+		infos = append(infos, ref.MultisigAccounts[level]...)
+	}
+	return NewMultisigGraph(infos...)
+}
+
+func sortedLevels(m map[int32][]*MultisigAccountInfo) []int32 {
+	levels := make([]int32, 0, len(m))
+	for l := range m {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels
+}
+
+func multisigKey(publicKey string) string {
+	return strings.ToUpper(publicKey)
+}
+
+func (g *MultisigGraph) addAccount(acc *PublicAccount) string {
+	key := multisigKey(acc.PublicKey)
+	if _, ok := g.accounts[key]; !ok {
+		g.accounts[key] = acc
+	}
+	return key
+}
+
+func (g *MultisigGraph) addEdge(multisig, cosigner string) {
+	for _, c := range g.cosigners[multisig] {
+		if c == cosigner {
+			return
+		}
+	}
+	g.cosigners[multisig] = append(g.cosigners[multisig], cosigner)
+	g.parents[cosigner] = append(g.parents[cosigner], multisig)
+}
+
+func (g *MultisigGraph) publicAccounts(keys []string) []*PublicAccount {
+	accs := make([]*PublicAccount, len(keys))
+	for i, k := range keys {
+		accs[i] = g.accounts[k]
+	}
+	return accs
+}
+
+// Account returns the account with the given public key, if it is in the graph.
+func (g *MultisigGraph) Account(publicKey string) (*PublicAccount, bool) {
+	acc, ok := g.accounts[multisigKey(publicKey)]
+	return acc, ok
+}
+
+// Multisig returns the multisig info of the account with the given public
+// key, or false if it is not a multisig account.
+func (g *MultisigGraph) Multisig(publicKey string) (*MultisigAccountInfo, bool) {
+	info, ok := g.multisigs[multisigKey(publicKey)]
+	return info, ok
+}
+
+// Accounts returns every account of the graph, sorted by public key.
+func (g *MultisigGraph) Accounts() []*PublicAccount {
+	keys := make([]string, 0, len(g.accounts))
+	for k := range g.accounts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return g.publicAccounts(keys)
+}
+
+// Cosigners returns the cosignatories of the account, direct and nested,
+// sorted by public key. Nested multisig accounts are included.
+func (g *MultisigGraph) Cosigners(publicKey string) []*PublicAccount {
+	return g.publicAccounts(g.reachable(multisigKey(publicKey), g.cosigners))
+}
+
+// Signers returns the keys which can eventually sign for the account: its
+// direct and nested cosignatories which are not multisig accounts themselves.
+func (g *MultisigGraph) Signers(publicKey string) []*PublicAccount {
+	keys := make([]string, 0)
+	for _, k := range g.reachable(multisigKey(publicKey), g.cosigners) {
+		if _, ok := g.multisigs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return g.publicAccounts(keys)
+}
+
+// MultisigAccountsOf returns the multisig accounts the key is a cosignatory
+// of, directly or through nested multisig accounts, sorted by public key.
+func (g *MultisigGraph) MultisigAccountsOf(publicKey string) []*PublicAccount {
+	return g.publicAccounts(g.reachable(multisigKey(publicKey), g.parents))
+}
+
+// ControlledBy returns the multisig accounts the key can approve
+// transactions for on its own, sorted by public key.
+func (g *MultisigGraph) ControlledBy(publicKey string) []*PublicAccount {
+	key := multisigKey(publicKey)
+
+	keys := make([]string, 0)
+	for _, m := range g.reachable(key, g.parents) {
+		for _, set := range g.ApprovalSets(m) {
+			if len(set) == 1 && set[0] == key {
+				keys = append(keys, m)
+				break
+			}
+		}
+	}
+	return g.publicAccounts(keys)
+}
+
+// reachable returns the keys reachable from key through edges, sorted.
+func (g *MultisigGraph) reachable(key string, edges map[string][]string) []string {
+	seen := map[string]bool{key: true}
+	stack := append([]string(nil), edges[key]...)
+	keys := make([]string, 0)
+
+	for len(stack) > 0 {
+		k := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+		stack = append(stack, edges[k]...)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// ApprovalSets returns the minimal sets of non multisig public keys whose
+// signatures approve a transaction of the account. At every level MinApproval
+// cosignatories have to sign, and a nested multisig cosignatory signs when
+// one of its own approval sets does. Each set and the result are sorted.
+// A non multisig account is approved by its own key.
+//
+// The number of sets grows combinatorially with the size of the graph.
+func (g *MultisigGraph) ApprovalSets(publicKey string) [][]string {
+	return g.keySets(multisigKey(publicKey), false, map[string]bool{})
+}
+
+// RemovalSets is like ApprovalSets, but uses MinRemoval for the account
+// itself, as required to remove one of its cosignatories. Nested multisig
+// cosignatories still need MinApproval.
+func (g *MultisigGraph) RemovalSets(publicKey string) [][]string {
+	return g.keySets(multisigKey(publicKey), true, map[string]bool{})
+}
+
+func (g *MultisigGraph) keySets(key string, removal bool, visiting map[string]bool) [][]string {
+	info, ok := g.multisigs[key]
+	if !ok {
+		return [][]string{{key}}
+	}
+
+	if visiting[key] {
+		return nil // a cycle can never be satisfied
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	min := int(info.MinApproval)
+	if removal {
+		min = int(info.MinRemoval)
+	}
+
+	cosigners := g.cosigners[key]
+	if min <= 0 || min > len(cosigners) {
+		min = len(cosigners)
+	}
+
+	options := make([][][]string, len(cosigners))
+	for i, c := range cosigners {
+		options[i] = g.keySets(c, false, visiting)
+	}
+
+	sets := make([][]string, 0)
+	combinations(len(cosigners), min, func(chosen []int) {
+		product := [][]string{{}}
+		for _, i := range chosen {
+			next := make([][]string, 0)
+			for _, p := range product {
+				for _, o := range options[i] {
+					next = append(next, unionKeys(p, o))
+				}
+			}
+			product = next
+		}
+		sets = append(sets, product...)
+	})
+
+	return minimalKeySets(sets)
+}
+
+// combinations calls fn with every k-combination of 0..n-1.
+func combinations(n, k int, fn func([]int)) {
+	chosen := make([]int, 0, k)
+
+	var rec func(start int)
+	rec = func(start int) {
+		if len(chosen) == k {
+			fn(chosen)
+			return
+		}
+		for i := start; i <= n-(k-len(chosen)); i++ {
+			chosen = append(chosen, i)
+			rec(i + 1)
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	rec(0)
+}
+
+func unionKeys(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, k := range a {
+		set[k] = true
+	}
+	for _, k := range b {
+		set[k] = true
+	}
+
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// minimalKeySets removes duplicate sets and supersets of other sets, and
+// sorts the remaining ones by size, then lexically.
+func minimalKeySets(sets [][]string) [][]string {
+	sort.Slice(sets, func(i, j int) bool {
+		if len(sets[i]) != len(sets[j]) {
+			return len(sets[i]) < len(sets[j])
+		}
+		return strings.Join(sets[i], ",") < strings.Join(sets[j], ",")
+	})
+
+	res := make([][]string, 0, len(sets))
+	for _, s := range sets {
+		redundant := false
+		for _, r := range res {
+			if isSubset(r, s) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func isSubset(a, b []string) bool {
+	set := make(map[string]bool, len(b))
+	for _, k := range b {
+		set[k] = true
+	}
+	for _, k := range a {
+		if !set[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// Cycles returns every cycle of cosignatory edges as the list of public keys
+// along it, starting from its smallest key. A valid graph has none.
+func (g *MultisigGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+
+	state := make(map[string]int, len(g.accounts))
+	path := make([]string, 0)
+	found := make(map[string][]string)
+
+	var visit func(k string)
+	visit = func(k string) {
+		state[k] = inProgress
+		path = append(path, k)
+
+		for _, c := range g.cosigners[k] {
+			switch state[c] {
+			case unvisited:
+				visit(c)
+			case inProgress:
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == c {
+						cycle := rotateCycle(path[i:])
+						found[strings.Join(cycle, ",")] = cycle
+						break
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[k] = done
+	}
+
+	keys := make([]string, 0, len(g.accounts))
+	for k := range g.accounts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if state[k] == unvisited {
+			visit(k)
+		}
+	}
+
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	cycles := make([][]string, len(ids))
+	for i, id := range ids {
+		cycles[i] = found[id]
+	}
+	return cycles
+}
+
+// rotateCycle returns a copy of cycle starting from its smallest key.
+func rotateCycle(cycle []string) []string {
+	min := 0
+	for i, k := range cycle {
+		if k < cycle[min] {
+			min = i
+		}
+	}
+	return append(append([]string(nil), cycle[min:]...), cycle[:min]...)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Multisig accounts
+// are drawn as boxes labelled with their minimum approval and removal, and
+// edges point from a multisig account to its cosignatories.
+func (g *MultisigGraph) WriteDOT(w io.Writer) error {
+	var b bytes.Buffer
+
+	b.WriteString("digraph multisig {\n")
+	for _, acc := range g.Accounts() {
+		key := multisigKey(acc.PublicKey)
+
+		label := acc.PublicKey
+		if acc.Address != nil {
+			label = acc.Address.Pretty()
+		}
+
+		if info, ok := g.multisigs[key]; ok {
+			fmt.Fprintf(&b, "\t%q [shape=box, label=%q];\n", key,
+				fmt.Sprintf("%s\nmin approval %d, min removal %d", label, info.MinApproval, info.MinRemoval))
+		} else {
+			fmt.Fprintf(&b, "\t%q [label=%q];\n", key, label)
+		}
+	}
+
+	for _, acc := range g.Accounts() {
+		key := multisigKey(acc.PublicKey)
+		for _, c := range g.cosigners[key] {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", key, c)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// DOT returns the graph in the Graphviz DOT language, see WriteDOT.
+func (g *MultisigGraph) DOT() string {
+	var b bytes.Buffer
+	g.WriteDOT(&b)
+	return b.String()
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func multisigTestAccount(t *testing.T, c string) *PublicAccount {
+	acc, err := NewAccountFromPublicKey(strings.Repeat(c, 64), MijinTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return acc
+}
+
+func multisigTestKey(c string) string {
+	return strings.Repeat(c, 64)
+}
+
+func multisigTestKeys(accs []*PublicAccount) []string {
+	keys := make([]string, len(accs))
+	for i, acc := range accs {
+		keys[i] = acc.PublicKey
+	}
+	return keys
+}
+
+// newTestTreasury returns the graph of T, which needs 2 of A and M to approve
+// and 1 to remove, where M needs 1 of B and C.
+func newTestTreasury(t *testing.T) *MultisigAccountGraphInfo {
+	var (
+		tr = multisigTestAccount(t, "F")
+		m  = multisigTestAccount(t, "E")
+		a  = multisigTestAccount(t, "A")
+		b  = multisigTestAccount(t, "B")
+		c  = multisigTestAccount(t, "C")
+	)
+
+	return &MultisigAccountGraphInfo{map[int32][]*MultisigAccountInfo{
+		0: {{Account: *tr, MinApproval: 2, MinRemoval: 1, Cosignatories: []*PublicAccount{a, m}}},
+		1: {
+			{Account: *m, MinApproval: 1, MinRemoval: 1, Cosignatories: []*PublicAccount{b, c}, MultisigAccounts: []*PublicAccount{tr}},
+			{Account: *a, MultisigAccounts: []*PublicAccount{tr}},
+		},
+		2: {
+			{Account: *b, MultisigAccounts: []*PublicAccount{m}},
+			{Account: *c, MultisigAccounts: []*PublicAccount{m}},
+		},
+	}}
+}
+
+func TestMultisigGraph_Traversal(t *testing.T) {
+	g := newTestTreasury(t).Graph()
+
+	var (
+		tr = multisigTestKey("F")
+		m  = multisigTestKey("E")
+		a  = multisigTestKey("A")
+		b  = multisigTestKey("B")
+		c  = multisigTestKey("C")
+	)
+
+	assert.Len(t, g.Accounts(), 5)
+
+	info, ok := g.Multisig(strings.ToLower(tr))
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), info.MinApproval)
+
+	_, ok = g.Multisig(a)
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{a, b, c, m}, multisigTestKeys(g.Cosigners(tr)))
+	assert.Equal(t, []string{a, b, c}, multisigTestKeys(g.Signers(tr)))
+	assert.Equal(t, []string{b, c}, multisigTestKeys(g.Signers(m)))
+	assert.Empty(t, g.Cosigners(a))
+
+	assert.Equal(t, []string{m, tr}, multisigTestKeys(g.MultisigAccountsOf(b)))
+	assert.Equal(t, []string{tr}, multisigTestKeys(g.MultisigAccountsOf(a)))
+	assert.Empty(t, g.MultisigAccountsOf(tr))
+
+	assert.Equal(t, []string{m}, multisigTestKeys(g.ControlledBy(b)))
+	assert.Empty(t, g.ControlledBy(a))
+}
+
+func TestMultisigGraph_ApprovalSets(t *testing.T) {
+	g := newTestTreasury(t).Graph()
+
+	var (
+		tr = multisigTestKey("F")
+		m  = multisigTestKey("E")
+		a  = multisigTestKey("A")
+		b  = multisigTestKey("B")
+		c  = multisigTestKey("C")
+	)
+
+	assert.Equal(t, [][]string{{a, b}, {a, c}}, g.ApprovalSets(tr))
+	assert.Equal(t, [][]string{{a}, {b}, {c}}, g.RemovalSets(tr))
+	assert.Equal(t, [][]string{{b}, {c}}, g.ApprovalSets(m))
+	assert.Equal(t, [][]string{{a}}, g.ApprovalSets(a))
+
+	// a key reachable through two branches only needs to sign once
+	d := multisigTestAccount(t, "D")
+	g = NewMultisigGraph(
+		&MultisigAccountInfo{Account: *multisigTestAccount(t, "F"), MinApproval: 2, Cosignatories: []*PublicAccount{multisigTestAccount(t, "E"), multisigTestAccount(t, "A")}},
+		&MultisigAccountInfo{Account: *multisigTestAccount(t, "E"), MinApproval: 1, Cosignatories: []*PublicAccount{multisigTestAccount(t, "A"), d}},
+	)
+	assert.Equal(t, [][]string{{a}}, g.ApprovalSets(tr))
+	assert.Equal(t, [][]string{{a}, {multisigTestKey("D")}}, g.ApprovalSets(m))
+}
+
+func TestMultisigGraph_Cycles(t *testing.T) {
+	assert.Empty(t, newTestTreasury(t).Graph().Cycles())
+
+	x, y, z := multisigTestAccount(t, "A"), multisigTestAccount(t, "B"), multisigTestAccount(t, "C")
+	g := NewMultisigGraph(
+		&MultisigAccountInfo{Account: *y, MinApproval: 1, Cosignatories: []*PublicAccount{z}},
+		&MultisigAccountInfo{Account: *z, MinApproval: 1, Cosignatories: []*PublicAccount{x}},
+		&MultisigAccountInfo{Account: *x, MinApproval: 1, Cosignatories: []*PublicAccount{y}},
+	)
+
+	assert.Equal(t, [][]string{{x.PublicKey, y.PublicKey, z.PublicKey}}, g.Cycles())
+	assert.Empty(t, g.ApprovalSets(x.PublicKey))
+	assert.Len(t, g.Cosigners(x.PublicKey), 2)
+}
+
+func TestMultisigGraph_DOT(t *testing.T) {
+	g := newTestTreasury(t).Graph()
+	dot := g.DOT()
+
+	tr, ok := g.Account(multisigTestKey("F"))
+	assert.True(t, ok)
+
+	assert.True(t, strings.HasPrefix(dot, "digraph multisig {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Contains(t, dot, `"`+multisigTestKey("F")+`" [shape=box, label="`+tr.Address.Pretty()+`\nmin approval 2, min removal 1"];`)
+	assert.Contains(t, dot, `"`+multisigTestKey("F")+`" -> "`+multisigTestKey("E")+`";`)
+	assert.Contains(t, dot, `"`+multisigTestKey("E")+`" -> "`+multisigTestKey("B")+`";`)
+	assert.Contains(t, dot, `"`+multisigTestKey("B")+`" [label="`+multisigTestAccount(t, "B").Address.Pretty()+`"];`)
+	assert.Equal(t, 5, strings.Count(dot, "label="))
+	assert.Equal(t, 4, strings.Count(dot, "->"))
+}
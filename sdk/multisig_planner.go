@@ -0,0 +1,240 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MultisigTarget is the desired state of a multisig account.
+// An empty Cosignatories list with zero minimums turns the account back
+// into a regular account.
+type MultisigTarget struct {
+	Cosignatories []*PublicAccount
+	MinApproval   int32
+	MinRemoval    int32
+}
+
+// MultisigModificationStep is one transaction of a MultisigModificationPlan.
+type MultisigModificationStep struct {
+	// Transaction is the modification, with the multisig account as signer.
+	Transaction *ModifyMultisigAccountTransaction
+	// Aggregate wraps Transaction. It is bonded whenever more than one
+	// account has to sign, so it has to be announced after a lock funds
+	// transaction; otherwise it is complete.
+	Aggregate *AggregateTransaction
+	// Signers are the accounts whose signatures count towards MinSignatures:
+	// the cosignatories of the multisig account before the step, or the
+	// account itself when it is converted into a multisig account.
+	Signers []*PublicAccount
+	// MinSignatures is the number of Signers which have to sign, the
+	// announcer included.
+	MinSignatures int
+	// OptIns are the added cosignatories, which all have to cosign to accept.
+	OptIns []*PublicAccount
+	// MinApproval and MinRemoval are the settings after the step.
+	MinApproval int32
+	MinRemoval  int32
+}
+
+func (ref *MultisigModificationStep) String() string {
+	return fmt.Sprintf(
+		`"Transaction": %s, "Signers": %s, "MinSignatures": %d, "OptIns": %s, "MinApproval": %d, "MinRemoval": %d`,
+		ref.Transaction,
+		ref.Signers,
+		ref.MinSignatures,
+		ref.OptIns,
+		ref.MinApproval,
+		ref.MinRemoval,
+	)
+}
+
+// MultisigModificationPlan is the ordered list of transactions which bring
+// a multisig account to a MultisigTarget. Each step must be confirmed before
+// the next one is announced, because its requirements assume the settings
+// left by the previous steps.
+type MultisigModificationPlan struct {
+	Steps []*MultisigModificationStep
+}
+
+// PlanMultisigModification computes the transactions which bring the
+// account described by current to target.
+//
+// Cosignatories are added and the minimums changed in a first step, then
+// cosignatories are removed one per transaction, as Catapult requires.
+// The minimums never exceed the number of cosignatories at any point, so
+// the account can not be locked out half way.
+func PlanMultisigModification(current *MultisigAccountInfo, target *MultisigTarget, deadline *Deadline, networkType NetworkType) (*MultisigModificationPlan, error) {
+	if current == nil {
+		return nil, ErrNilMultisigAccountInfo
+	}
+	if target == nil {
+		return nil, ErrNilMultisigTarget
+	}
+	if err := validateMultisigTarget(&current.Account, target); err != nil {
+		return nil, err
+	}
+
+	var (
+		account   = current.Account
+		cosigners = current.Cosignatories
+		approval  = current.MinApproval
+		removal   = current.MinRemoval
+		adds      = diffCosignatories(target.Cosignatories, cosigners)
+		removes   = diffCosignatories(cosigners, target.Cosignatories)
+		plan      = &MultisigModificationPlan{Steps: make([]*MultisigModificationStep, 0)}
+	)
+
+	// with no cosignatory left to remove, the minimums can be set right
+	// away; otherwise the removals bring them down
+	newApproval, newRemoval := target.MinApproval, target.MinRemoval
+	if len(target.Cosignatories) == 0 {
+		newApproval, newRemoval = approval, removal
+	}
+
+	if len(adds) > 0 || newApproval != approval || newRemoval != removal {
+		mods := make([]*MultisigCosignatoryModification, len(adds))
+		for i, a := range adds {
+			mods[i] = &MultisigCosignatoryModification{Add, a}
+		}
+
+		step, err := newMultisigStep(&account, cosigners, approval, removal, newApproval, newRemoval, mods, deadline, networkType)
+		if err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, step)
+
+		cosigners = append(append([]*PublicAccount(nil), cosigners...), adds...)
+		approval, removal = newApproval, newRemoval
+	}
+
+	for _, r := range removes {
+		left := int32(len(cosigners) - 1)
+
+		newApproval, newRemoval := approval, removal
+		if newApproval > left {
+			newApproval = left
+		}
+		if newRemoval > left {
+			newRemoval = left
+		}
+
+		mods := []*MultisigCosignatoryModification{{Remove, r}}
+
+		step, err := newMultisigStep(&account, cosigners, approval, removal, newApproval, newRemoval, mods, deadline, networkType)
+		if err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, step)
+
+		cosigners = diffCosignatories(cosigners, []*PublicAccount{r})
+		approval, removal = newApproval, newRemoval
+	}
+
+	return plan, nil
+}
+
+func newMultisigStep(account *PublicAccount, cosigners []*PublicAccount, approval, removal, newApproval, newRemoval int32,
+	mods []*MultisigCosignatoryModification, deadline *Deadline, networkType NetworkType) (*MultisigModificationStep, error) {
+
+	tx, err := NewModifyMultisigAccountTransaction(deadline, int(newApproval-approval), int(newRemoval-removal), mods, networkType)
+	if err != nil {
+		return nil, err
+	}
+	tx.ToAggregate(account)
+
+	step := &MultisigModificationStep{
+		Transaction: tx,
+		OptIns:      make([]*PublicAccount, 0),
+		MinApproval: newApproval,
+		MinRemoval:  newRemoval,
+	}
+
+	for _, m := range mods {
+		if m.Type == Add {
+			step.OptIns = append(step.OptIns, m.PublicAccount)
+		}
+	}
+
+	switch {
+	case len(cosigners) == 0:
+		// a regular account being converted signs for itself
+		step.Signers = []*PublicAccount{account}
+		step.MinSignatures = 1
+	case len(mods) == 1 && mods[0].Type == Remove:
+		step.Signers = cosigners
+		step.MinSignatures = int(removal)
+	default:
+		step.Signers = cosigners
+		step.MinSignatures = int(approval)
+	}
+
+	if step.MinSignatures+len(step.OptIns) > 1 {
+		step.Aggregate, err = NewBondedAggregateTransaction(deadline, []Transaction{tx}, networkType)
+	} else {
+		step.Aggregate, err = NewCompleteAggregateTransaction(deadline, []Transaction{tx}, networkType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+func validateMultisigTarget(account *PublicAccount, target *MultisigTarget) error {
+	seen := make(map[string]bool, len(target.Cosignatories))
+	for _, c := range target.Cosignatories {
+		if c == nil {
+			return ErrNilAccount
+		}
+
+		key := multisigKey(c.PublicKey)
+		if key == multisigKey(account.PublicKey) {
+			return ErrSelfCosignatory
+		}
+		if seen[key] {
+			return ErrDuplicateCosignatory
+		}
+		seen[key] = true
+	}
+
+	n := int32(len(target.Cosignatories))
+	if n == 0 {
+		if target.MinApproval != 0 || target.MinRemoval != 0 {
+			return ErrInvalidMinApproval
+		}
+		return nil
+	}
+
+	if target.MinApproval < 1 || target.MinApproval > n {
+		return ErrInvalidMinApproval
+	}
+	if target.MinRemoval < 1 || target.MinRemoval > n {
+		return ErrInvalidMinRemoval
+	}
+	return nil
+}
+
+// diffCosignatories returns the accounts of a which are not in b, sorted by
+// public key.
+func diffCosignatories(a, b []*PublicAccount) []*PublicAccount {
+	in := make(map[string]bool, len(b))
+	for _, acc := range b {
+		in[multisigKey(acc.PublicKey)] = true
+	}
+
+	res := make([]*PublicAccount, 0)
+	for _, acc := range a {
+		if !in[multisigKey(acc.PublicKey)] {
+			res = append(res, acc)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return multisigKey(res[i].PublicKey) < multisigKey(res[j].PublicKey)
+	})
+	return res
+}
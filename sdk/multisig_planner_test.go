@@ -0,0 +1,124 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPlanMultisigModification_Convert(t *testing.T) {
+	acc := multisigTestAccount(t, "F")
+	a, b := multisigTestAccount(t, "A"), multisigTestAccount(t, "B")
+
+	plan, err := PlanMultisigModification(&MultisigAccountInfo{Account: *acc}, &MultisigTarget{
+		Cosignatories: []*PublicAccount{b, a},
+		MinApproval:   1,
+		MinRemoval:    2,
+	}, NewDeadline(time.Hour), MijinTest)
+	assert.Nilf(t, err, "PlanMultisigModification returned error: %s", err)
+	assert.Len(t, plan.Steps, 1)
+
+	step := plan.Steps[0]
+	assert.Equal(t, acc, step.Transaction.Signer)
+	assert.Equal(t, 1, step.Transaction.MinApprovalDelta)
+	assert.Equal(t, 2, step.Transaction.MinRemovalDelta)
+	assert.Equal(t, []*MultisigCosignatoryModification{{Add, a}, {Add, b}}, step.Transaction.Modifications)
+	assert.Equal(t, AggregateBonded, step.Aggregate.Type)
+	assert.Equal(t, []Transaction{step.Transaction}, step.Aggregate.InnerTransactions)
+	assert.Equal(t, []*PublicAccount{acc}, step.Signers)
+	assert.Equal(t, 1, step.MinSignatures)
+	assert.Equal(t, []*PublicAccount{a, b}, step.OptIns)
+}
+
+func TestPlanMultisigModification_Replace(t *testing.T) {
+	acc := multisigTestAccount(t, "F")
+	a, b, c, d := multisigTestAccount(t, "A"), multisigTestAccount(t, "B"), multisigTestAccount(t, "C"), multisigTestAccount(t, "D")
+
+	// 2 of a, b, c -> 1 of c, d with min removal 1
+	plan, err := PlanMultisigModification(&MultisigAccountInfo{
+		Account:       *acc,
+		MinApproval:   2,
+		MinRemoval:    2,
+		Cosignatories: []*PublicAccount{a, b, c},
+	}, &MultisigTarget{
+		Cosignatories: []*PublicAccount{c, d},
+		MinApproval:   1,
+		MinRemoval:    1,
+	}, NewDeadline(time.Hour), MijinTest)
+	assert.Nilf(t, err, "PlanMultisigModification returned error: %s", err)
+	assert.Len(t, plan.Steps, 3)
+
+	add := plan.Steps[0]
+	assert.Equal(t, -1, add.Transaction.MinApprovalDelta)
+	assert.Equal(t, -1, add.Transaction.MinRemovalDelta)
+	assert.Equal(t, []*MultisigCosignatoryModification{{Add, d}}, add.Transaction.Modifications)
+	assert.Equal(t, []*PublicAccount{a, b, c}, add.Signers)
+	assert.Equal(t, 2, add.MinSignatures)
+	assert.Equal(t, []*PublicAccount{d}, add.OptIns)
+	assert.Equal(t, AggregateBonded, add.Aggregate.Type)
+
+	for i, removed := range []*PublicAccount{a, b} {
+		step := plan.Steps[i+1]
+		assert.Equal(t, 0, step.Transaction.MinApprovalDelta)
+		assert.Equal(t, 0, step.Transaction.MinRemovalDelta)
+		assert.Equal(t, []*MultisigCosignatoryModification{{Remove, removed}}, step.Transaction.Modifications)
+		assert.Equal(t, 1, step.MinSignatures)
+		assert.Empty(t, step.OptIns)
+		assert.Equal(t, AggregateCompleted, step.Aggregate.Type)
+	}
+	assert.Len(t, plan.Steps[1].Signers, 4)
+	assert.Len(t, plan.Steps[2].Signers, 3)
+}
+
+func TestPlanMultisigModification_Revert(t *testing.T) {
+	acc := multisigTestAccount(t, "F")
+	a, b := multisigTestAccount(t, "A"), multisigTestAccount(t, "B")
+
+	plan, err := PlanMultisigModification(&MultisigAccountInfo{
+		Account:       *acc,
+		MinApproval:   2,
+		MinRemoval:    2,
+		Cosignatories: []*PublicAccount{a, b},
+	}, &MultisigTarget{}, NewDeadline(time.Hour), MijinTest)
+	assert.Nilf(t, err, "PlanMultisigModification returned error: %s", err)
+	assert.Len(t, plan.Steps, 2)
+
+	// the minimums follow the number of cosignatories down to zero
+	assert.Equal(t, -1, plan.Steps[0].Transaction.MinApprovalDelta)
+	assert.Equal(t, 2, plan.Steps[0].MinSignatures)
+	assert.Equal(t, AggregateBonded, plan.Steps[0].Aggregate.Type)
+	assert.Equal(t, -1, plan.Steps[1].Transaction.MinRemovalDelta)
+	assert.Equal(t, 1, plan.Steps[1].MinSignatures)
+	assert.Equal(t, int32(0), plan.Steps[1].MinApproval)
+	assert.Equal(t, int32(0), plan.Steps[1].MinRemoval)
+}
+
+func TestPlanMultisigModification_Invalid(t *testing.T) {
+	acc := multisigTestAccount(t, "F")
+	a, b := multisigTestAccount(t, "A"), multisigTestAccount(t, "B")
+	current := &MultisigAccountInfo{Account: *acc, MinApproval: 1, MinRemoval: 1, Cosignatories: []*PublicAccount{a}}
+
+	for target, expected := range map[*MultisigTarget]error{
+		nil: ErrNilMultisigTarget,
+		{Cosignatories: []*PublicAccount{a, b}, MinApproval: 3, MinRemoval: 1}:   ErrInvalidMinApproval,
+		{Cosignatories: []*PublicAccount{a, b}, MinApproval: 0, MinRemoval: 1}:   ErrInvalidMinApproval,
+		{Cosignatories: []*PublicAccount{a, b}, MinApproval: 1, MinRemoval: 3}:   ErrInvalidMinRemoval,
+		{Cosignatories: []*PublicAccount{a, a}, MinApproval: 1, MinRemoval: 1}:   ErrDuplicateCosignatory,
+		{Cosignatories: []*PublicAccount{a, acc}, MinApproval: 1, MinRemoval: 1}: ErrSelfCosignatory,
+		{MinApproval: 1}: ErrInvalidMinApproval,
+	} {
+		_, err := PlanMultisigModification(current, target, NewDeadline(time.Hour), MijinTest)
+		assert.Equal(t, expected, err)
+	}
+
+	_, err := PlanMultisigModification(nil, &MultisigTarget{}, NewDeadline(time.Hour), MijinTest)
+	assert.Equal(t, ErrNilMultisigAccountInfo, err)
+
+	plan, err := PlanMultisigModification(current, &MultisigTarget{Cosignatories: []*PublicAccount{a}, MinApproval: 1, MinRemoval: 1}, NewDeadline(time.Hour), MijinTest)
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Steps)
+}
@@ -0,0 +1,337 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ValidateNamespaceName checks a full namespace name, e.g. "foo.bar", has at
+// most three parts and that each of them is a valid namespace name.
+func ValidateNamespaceName(name string) error {
+	_, err := GenerateNamespacePath(name)
+	return err
+}
+
+// ValidateMosaicName checks a full mosaic name, e.g. "foo.bar:coin", has a
+// valid namespace name and a valid mosaic name.
+func ValidateMosaicName(fullName string) error {
+	_, err := NewMosaicIdFromFullName(fullName)
+	return err
+}
+
+// NewMosaicIdFromName generates the mosaic ID of mosaicName in the namespace
+// namespaceName, without network access.
+func NewMosaicIdFromName(namespaceName string, mosaicName string) (*MosaicId, error) {
+	return generateMosaicId(namespaceName, mosaicName)
+}
+
+// NewNamespacePath generates the NamespaceId of every level of name, from the
+// root namespace down to name itself.
+func NewNamespacePath(name string) ([]*NamespaceId, error) {
+	path, err := GenerateNamespacePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	nsIds := make([]*NamespaceId, len(path))
+	for i, id := range path {
+		nsIds[i] = bigIntToNamespaceId(id)
+	}
+
+	return nsIds, nil
+}
+
+// NameDictionary maps mosaic and namespace IDs back to their full names
+// without network access. Names are added by hand, loaded from a file, or
+// learned from the responses of NamespaceService.GetNamespaceNames and
+// MosaicService.GetMosaicNames. It is safe for concurrent use.
+type NameDictionary struct {
+	mu sync.RWMutex
+	// namespaces and mosaics map IDs, as returned by String(), to full names
+	namespaces map[string]string
+	mosaics    map[string]string
+	// learned names whose parent namespace is not known yet
+	pendingNamespaces map[string]*NamespaceName
+	pendingMosaics    map[string]*MosaicName
+}
+
+// NewNameDictionary returns an empty NameDictionary.
+func NewNameDictionary() *NameDictionary {
+	return &NameDictionary{
+		namespaces:        make(map[string]string),
+		mosaics:           make(map[string]string),
+		pendingNamespaces: make(map[string]*NamespaceName),
+		pendingMosaics:    make(map[string]*MosaicName),
+	}
+}
+
+// LoadNameDictionary returns a NameDictionary with the names of the file at
+// path, in the format read by NameDictionary.Load.
+func LoadNameDictionary(path string) (*NameDictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d := NewNameDictionary()
+	if err := d.Load(f); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Load adds the names read from r, one full name per line. Names containing
+// a colon, e.g. "foo.bar:coin", are mosaic names, the others namespace names.
+// Blank lines and lines starting with '#' are skipped.
+func (d *NameDictionary) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+
+		var err error
+		if strings.Contains(name, ":") {
+			err = d.AddMosaic(name)
+		} else {
+			err = d.AddNamespace(name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("line %d: %q: %v", line, name, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Save writes the names of the dictionary to w, sorted, in the format read by
+// Load.
+func (d *NameDictionary) Save(w io.Writer) error {
+	d.mu.RLock()
+	names := make([]string, 0, len(d.namespaces)+len(d.mosaics))
+	for _, name := range d.namespaces {
+		names = append(names, name)
+	}
+	for _, name := range d.mosaics {
+		names = append(names, name)
+	}
+	d.mu.RUnlock()
+
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		if _, err := bw.WriteString(name + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// AddNamespace adds the full namespace name along with the names of its
+// parent namespaces.
+func (d *NameDictionary) AddNamespace(name string) error {
+	path, err := GenerateNamespacePath(name)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(name, ".")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, id := range path {
+		d.namespaces[id.String()] = strings.Join(parts[:i+1], ".")
+	}
+
+	d.resolvePending()
+
+	return nil
+}
+
+// AddMosaic adds the full mosaic name, e.g. "foo.bar:coin", along with the
+// names of its namespaces.
+func (d *NameDictionary) AddMosaic(fullName string) error {
+	mosaicId, err := NewMosaicIdFromFullName(fullName)
+	if err != nil {
+		return err
+	}
+
+	if err := d.AddNamespace(fullName[:strings.Index(fullName, ":")]); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.mosaics[mosaicId.String()] = fullName
+
+	return nil
+}
+
+// LearnNamespaceNames adds the names returned by
+// NamespaceService.GetNamespaceNames. Each ID is checked against the one
+// generated from the name and the parent ID; on a mismatch
+// ErrNameIdMismatch is returned and none of the names are added. Names whose
+// parent is unknown are kept until the parent is added.
+func (d *NameDictionary) LearnNamespaceNames(nsNames []*NamespaceName) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, nsName := range nsNames {
+		if nsName == nil || nsName.NamespaceId == nil {
+			return ErrNilNamespaceId
+		}
+
+		if !regValidNamespace.MatchString(nsName.Name) {
+			return ErrInvalidNamespaceName
+		}
+
+		id, err := generateId(nsName.Name, namespaceIdToBigInt(parentOrZero(nsName.ParentId)))
+		if err != nil {
+			return err
+		}
+
+		if id.String() != nsName.NamespaceId.String() {
+			return ErrNameIdMismatch
+		}
+	}
+
+	for _, nsName := range nsNames {
+		d.pendingNamespaces[nsName.NamespaceId.String()] = nsName
+	}
+
+	d.resolvePending()
+
+	return nil
+}
+
+// LearnMosaicNames adds the names returned by MosaicService.GetMosaicNames,
+// checking IDs the way LearnNamespaceNames does. The full name of a mosaic
+// is known once its namespace is.
+func (d *NameDictionary) LearnMosaicNames(mscNames []*MosaicName) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, mscName := range mscNames {
+		if mscName == nil || mscName.MosaicId == nil {
+			return ErrNilMosaicId
+		}
+
+		if mscName.ParentId == nil || namespaceIdToBigInt(mscName.ParentId).Sign() == 0 {
+			return ErrNilNamespaceId
+		}
+
+		if !regValidMosaicName.MatchString(mscName.Name) {
+			return ErrInvalidMosaicName
+		}
+
+		id, err := generateId(mscName.Name, namespaceIdToBigInt(mscName.ParentId))
+		if err != nil {
+			return err
+		}
+
+		if id.String() != mscName.MosaicId.String() {
+			return ErrNameIdMismatch
+		}
+	}
+
+	for _, mscName := range mscNames {
+		d.pendingMosaics[mscName.MosaicId.String()] = mscName
+	}
+
+	d.resolvePending()
+
+	return nil
+}
+
+// resolvePending moves learned names whose parent namespace became known to
+// the dictionary. It should be called with the lock held.
+func (d *NameDictionary) resolvePending() {
+	for resolved := true; resolved; {
+		resolved = false
+
+		for id, nsName := range d.pendingNamespaces {
+			name := nsName.Name
+
+			if parent := parentOrZero(nsName.ParentId); namespaceIdToBigInt(parent).Sign() != 0 {
+				parentName, ok := d.namespaces[parent.String()]
+				if !ok {
+					continue
+				}
+
+				name = parentName + "." + name
+			}
+
+			d.namespaces[id] = name
+			delete(d.pendingNamespaces, id)
+			resolved = true
+		}
+	}
+
+	for id, mscName := range d.pendingMosaics {
+		if nsName, ok := d.namespaces[mscName.ParentId.String()]; ok {
+			d.mosaics[id] = nsName + ":" + mscName.Name
+			delete(d.pendingMosaics, id)
+		}
+	}
+}
+
+// NamespaceName returns the full name of the namespace, if known.
+func (d *NameDictionary) NamespaceName(nsId *NamespaceId) (string, bool) {
+	if nsId == nil {
+		return "", false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	name, ok := d.namespaces[nsId.String()]
+	return name, ok
+}
+
+// MosaicName returns the full name of the mosaic, if known.
+func (d *NameDictionary) MosaicName(mosaicId *MosaicId) (string, bool) {
+	if mosaicId == nil {
+		return "", false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	name, ok := d.mosaics[mosaicId.String()]
+	return name, ok
+}
+
+// Len returns the number of known namespace and mosaic names.
+func (d *NameDictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return len(d.namespaces) + len(d.mosaics)
+}
+
+func parentOrZero(parentId *NamespaceId) *NamespaceId {
+	if parentId == nil {
+		return bigIntToNamespaceId(big.NewInt(0))
+	}
+
+	return parentId
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestValidateNames(t *testing.T) {
+	assert.Nil(t, ValidateNamespaceName("foo.bar-baz.q_x"))
+	assert.Equal(t, ErrInvalidNamespaceName, ValidateNamespaceName("Foo"))
+	assert.Equal(t, ErrInvalidNamespaceName, ValidateNamespaceName("foo..bar"))
+	assert.Equal(t, ErrNamespaceTooManyPart, ValidateNamespaceName("a.b.c.d"))
+
+	assert.Nil(t, ValidateMosaicName("nem:xem"))
+	assert.Equal(t, ErrInvalidMosaicName, ValidateMosaicName("nem"))
+	assert.Equal(t, ErrInvalidMosaicName, ValidateMosaicName("nem:-xem"))
+}
+
+func TestNewNamespacePath(t *testing.T) {
+	path, err := NewNamespacePath("nem.xem")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(path))
+	assert.Equal(t, big.NewInt(-8884663987180930485).Int64(), namespaceIdToBigInt(path[0]).Int64())
+	assert.Equal(t, big.NewInt(-3087871471161192663).Int64(), namespaceIdToBigInt(path[1]).Int64())
+
+	mosaicId, err := NewMosaicIdFromName("nem", "xem")
+	assert.Nil(t, err)
+	assert.Equal(t, XemMosaicId.String(), mosaicId.String())
+}
+
+func TestNameDictionary_AddAndLoad(t *testing.T) {
+	d := NewNameDictionary()
+	assert.Nil(t, d.AddMosaic("nem:xem"))
+	assert.Nil(t, d.AddNamespace("foo.bar"))
+	assert.Equal(t, 4, d.Len())
+
+	name, ok := d.MosaicName(XemMosaicId)
+	assert.True(t, ok)
+	assert.Equal(t, "nem:xem", name)
+
+	nsId, err := NewNamespaceIdFromName("foo")
+	assert.Nil(t, err)
+	name, ok = d.NamespaceName(nsId)
+	assert.True(t, ok)
+	assert.Equal(t, "foo", name)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, d.Save(buf))
+	assert.Equal(t, "foo\nfoo.bar\nnem\nnem:xem\n", buf.String())
+
+	loaded := NewNameDictionary()
+	assert.Nil(t, loaded.Load(strings.NewReader("# names\n\nfoo.bar\n nem:xem \n")))
+	assert.Equal(t, 4, loaded.Len())
+
+	err = loaded.Load(strings.NewReader("foo\nfoo:Bad\n"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestNameDictionary_Learn(t *testing.T) {
+	path, err := NewNamespacePath("foo.bar")
+	assert.Nil(t, err)
+	mosaicId, err := NewMosaicIdFromName("foo.bar", "coin")
+	assert.Nil(t, err)
+
+	d := NewNameDictionary()
+
+	// the mosaic and the child namespace are learned before the root
+	assert.Nil(t, d.LearnMosaicNames([]*MosaicName{{MosaicId: mosaicId, Name: "coin", ParentId: path[1]}}))
+	assert.Nil(t, d.LearnNamespaceNames([]*NamespaceName{{NamespaceId: path[1], Name: "bar", ParentId: path[0]}}))
+	_, ok := d.MosaicName(mosaicId)
+	assert.False(t, ok)
+
+	assert.Nil(t, d.LearnNamespaceNames([]*NamespaceName{{NamespaceId: path[0], Name: "foo", ParentId: bigIntToNamespaceId(big.NewInt(0))}}))
+
+	name, ok := d.NamespaceName(path[1])
+	assert.True(t, ok)
+	assert.Equal(t, "foo.bar", name)
+
+	name, ok = d.MosaicName(mosaicId)
+	assert.True(t, ok)
+	assert.Equal(t, "foo.bar:coin", name)
+
+	err = d.LearnNamespaceNames([]*NamespaceName{{NamespaceId: path[0], Name: "baz", ParentId: nil}})
+	assert.Equal(t, ErrNameIdMismatch, err)
+	name, _ = d.NamespaceName(path[0])
+	assert.Equal(t, "foo", name)
+}
@@ -0,0 +1,269 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"golang.org/x/net/context"
+	"strings"
+)
+
+// NamedTransaction is a transaction annotated with the full names of the
+// mosaics and namespaces it refers to, including those of the inner
+// transactions of an aggregate.
+type NamedTransaction struct {
+	Transaction
+	// MosaicNames maps MosaicId.String() to full names, e.g. "nem:xem".
+	MosaicNames map[string]string
+	// NamespaceNames maps NamespaceId.String() to full names, e.g. "foo.bar".
+	NamespaceNames map[string]string
+}
+
+// MosaicName returns the full name of the mosaic, or its hexadecimal id when
+// the name is unknown.
+func (tx *NamedTransaction) MosaicName(mosaicId *MosaicId) string {
+	if mosaicId == nil {
+		return ""
+	}
+
+	if name, ok := tx.MosaicNames[mosaicId.String()]; ok {
+		return name
+	}
+
+	return mosaicId.toHexString()
+}
+
+// NamespaceName returns the full name of the namespace, or its hexadecimal
+// id when the name is unknown.
+func (tx *NamedTransaction) NamespaceName(nsId *NamespaceId) string {
+	if nsId == nil {
+		return ""
+	}
+
+	if name, ok := tx.NamespaceNames[nsId.String()]; ok {
+		return name
+	}
+
+	return nsId.toHexString()
+}
+
+// ResolveNames returns txs, in the same order, annotated with the full names
+// of the mosaics and namespaces they refer to. The names of all transactions
+// are requested together, through MosaicService.GetMosaicNames and
+// NamespaceService.GetNamespaceNames, and unknown ids are left out. If only
+// some name requests fail, the transactions are returned along with a
+// *BatchError.
+func (txs *TransactionService) ResolveNames(ctx context.Context, transactions []Transaction) ([]*NamedTransaction, error) {
+	r := &nameResolver{
+		client:     txs.client,
+		mosaicIds:  make(map[string]*MosaicId),
+		nsIds:      make(map[string]*NamespaceId),
+		mosaics:    make(map[string]*MosaicName),
+		namespaces: make(map[string]*NamespaceName),
+	}
+
+	refs := make([]*nameRefs, len(transactions))
+	for i, tx := range transactions {
+		refs[i] = newNameRefs()
+		refs[i].add(tx)
+
+		for id, mosaicId := range refs[i].mosaicIds {
+			r.mosaicIds[id] = mosaicId
+		}
+		for id, nsId := range refs[i].nsIds {
+			r.nsIds[id] = nsId
+		}
+	}
+
+	err := r.resolve(ctx)
+	if batchFailed(err) {
+		return nil, err
+	}
+
+	named := make([]*NamedTransaction, len(transactions))
+	for i, tx := range transactions {
+		named[i] = &NamedTransaction{
+			Transaction:    tx,
+			MosaicNames:    make(map[string]string),
+			NamespaceNames: make(map[string]string),
+		}
+
+		for id := range refs[i].mosaicIds {
+			if name := r.mosaicFullName(id); name != "" {
+				named[i].MosaicNames[id] = name
+			}
+		}
+		for id := range refs[i].nsIds {
+			if name := r.namespaceFullName(id); name != "" {
+				named[i].NamespaceNames[id] = name
+			}
+		}
+	}
+
+	return named, err
+}
+
+// nameRefs collects the mosaics and namespaces a transaction refers to.
+type nameRefs struct {
+	mosaicIds map[string]*MosaicId
+	nsIds     map[string]*NamespaceId
+}
+
+func newNameRefs() *nameRefs {
+	return &nameRefs{
+		mosaicIds: make(map[string]*MosaicId),
+		nsIds:     make(map[string]*NamespaceId),
+	}
+}
+
+func (r *nameRefs) add(tx Transaction) {
+	switch tx := tx.(type) {
+	case *AggregateTransaction:
+		for _, inner := range tx.InnerTransactions {
+			r.add(inner)
+		}
+	case *TransferTransaction:
+		for _, m := range tx.Mosaics {
+			r.addMosaic(m)
+		}
+	case *LockFundsTransaction:
+		r.addMosaic(tx.Mosaic)
+	case *SecretLockTransaction:
+		r.addMosaic(tx.Mosaic)
+	case *MosaicDefinitionTransaction:
+		r.addMosaicId(tx.MosaicId)
+		r.addNamespaceId(tx.NamespaceId)
+	case *MosaicSupplyChangeTransaction:
+		r.addMosaicId(tx.MosaicId)
+	case *RegisterNamespaceTransaction:
+		r.addNamespaceId(tx.NamespaceId)
+		r.addNamespaceId(tx.ParentId)
+	}
+}
+
+func (r *nameRefs) addMosaic(m *Mosaic) {
+	if m != nil {
+		r.addMosaicId(m.MosaicId)
+	}
+}
+
+func (r *nameRefs) addMosaicId(mosaicId *MosaicId) {
+	if mosaicId != nil && mosaicIdToBigInt(mosaicId).Sign() != 0 {
+		r.mosaicIds[mosaicId.String()] = mosaicId
+	}
+}
+
+func (r *nameRefs) addNamespaceId(nsId *NamespaceId) {
+	if nsId != nil && namespaceIdToBigInt(nsId).Sign() != 0 {
+		r.nsIds[nsId.String()] = nsId
+	}
+}
+
+// nameResolver requests the names of mosaics and namespaces, along with the
+// names of their parent namespaces.
+type nameResolver struct {
+	client     *Client
+	mosaicIds  map[string]*MosaicId
+	nsIds      map[string]*NamespaceId
+	mosaics    map[string]*MosaicName
+	namespaces map[string]*NamespaceName
+}
+
+func (r *nameResolver) resolve(ctx context.Context) error {
+	var partial error
+
+	if len(r.mosaicIds) > 0 {
+		mscIds := make([]*MosaicId, 0, len(r.mosaicIds))
+		for _, mosaicId := range r.mosaicIds {
+			mscIds = append(mscIds, mosaicId)
+		}
+
+		mscNames, err := r.client.Mosaic.GetMosaicNames(ctx, mscIds)
+		if err != nil {
+			return err
+		}
+
+		for _, mscName := range mscNames {
+			r.mosaics[mscName.MosaicId.String()] = mscName
+			if mscName.ParentId != nil && namespaceIdToBigInt(mscName.ParentId).Sign() != 0 {
+				r.nsIds[mscName.ParentId.String()] = mscName.ParentId
+			}
+		}
+	}
+
+	requested := make(map[string]bool)
+	pending := make([]*NamespaceId, 0, len(r.nsIds))
+	for id, nsId := range r.nsIds {
+		requested[id] = true
+		pending = append(pending, nsId)
+	}
+
+	// each round requests the parents of the names of the previous one
+	for len(pending) > 0 {
+		nsNames, err := r.client.Namespace.GetNamespaceNames(ctx, pending)
+		if batchFailed(err) {
+			return err
+		}
+		if err != nil {
+			partial = err
+		}
+
+		pending = pending[:0]
+		for _, nsName := range nsNames {
+			r.namespaces[nsName.NamespaceId.String()] = nsName
+
+			parent := nsName.ParentId
+			if parent == nil || namespaceIdToBigInt(parent).Sign() == 0 || requested[parent.String()] {
+				continue
+			}
+
+			requested[parent.String()] = true
+			pending = append(pending, parent)
+		}
+	}
+
+	return partial
+}
+
+// namespaceFullName joins the names from the root namespace down to id, or
+// returns "" when any of them is unknown.
+func (r *nameResolver) namespaceFullName(id string) string {
+	parts := make([]string, 0, 3)
+
+	for seen := 0; seen <= len(r.namespaces); seen++ {
+		nsName, ok := r.namespaces[id]
+		if !ok {
+			return ""
+		}
+
+		parts = append([]string{nsName.Name}, parts...)
+
+		if nsName.ParentId == nil || namespaceIdToBigInt(nsName.ParentId).Sign() == 0 {
+			return strings.Join(parts, ".")
+		}
+
+		id = nsName.ParentId.String()
+	}
+
+	// the parents form a cycle
+	return ""
+}
+
+func (r *nameResolver) mosaicFullName(id string) string {
+	mscName, ok := r.mosaics[id]
+	if !ok {
+		return ""
+	}
+
+	if mscName.ParentId == nil || namespaceIdToBigInt(mscName.ParentId).Sign() == 0 {
+		return mscName.Name
+	}
+
+	nsName := r.namespaceFullName(mscName.ParentId.String())
+	if nsName == "" {
+		return ""
+	}
+
+	return nsName + ":" + mscName.Name
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestTransactionService_ResolveNames(t *testing.T) {
+	// namespaces foo (1), foo.bar (2) and foo.bar.baz (3), mosaic foo.bar:coin (10)
+	nsNames := []string{
+		`{"namespaceId": [1, 0], "name": "foo", "parentId": [0, 0]}`,
+		`{"namespaceId": [2, 0], "name": "bar", "parentId": [1, 0]}`,
+		`{"namespaceId": [3, 0], "name": "baz", "parentId": [2, 0]}`,
+	}
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     mosaicNamesRoute,
+		RespBody: `[{"mosaicId": [10, 0], "name": "coin", "parentId": [2, 0]}]`,
+	})
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     namespaceNamesRoute,
+		RespBody: "[" + strings.Join(nsNames, ",") + "]",
+	})
+
+	client := m.getTestNetClientUnsafe()
+
+	coin := bigIntToMosaicId(big.NewInt(10))
+	unknown := bigIntToMosaicId(big.NewInt(11))
+	bar, baz := bigIntToNamespaceId(big.NewInt(2)), bigIntToNamespaceId(big.NewInt(3))
+
+	transfer := &TransferTransaction{
+		Mosaics: []*Mosaic{{coin, big.NewInt(1)}, {unknown, big.NewInt(2)}},
+	}
+	aggregate := &AggregateTransaction{InnerTransactions: []Transaction{
+		transfer,
+		&RegisterNamespaceTransaction{NamespaceId: baz, ParentId: bar},
+	}}
+
+	named, err := client.Transaction.ResolveNames(ctx, []Transaction{transfer, aggregate, &SecretProofTransaction{}})
+	assert.Nilf(t, err, "TransactionService.ResolveNames returned error: %s", err)
+	assert.Len(t, named, 3)
+
+	assert.Equal(t, transfer, named[0].Transaction)
+	assert.Equal(t, map[string]string{"10": "foo.bar:coin"}, named[0].MosaicNames)
+	assert.Equal(t, "foo.bar:coin", named[0].MosaicName(coin))
+	assert.Equal(t, unknown.toHexString(), named[0].MosaicName(unknown))
+	assert.Len(t, named[0].NamespaceNames, 0)
+
+	assert.Equal(t, map[string]string{"2": "foo.bar", "3": "foo.bar.baz"}, named[1].NamespaceNames)
+	assert.Equal(t, "foo.bar:coin", named[1].MosaicName(coin))
+
+	assert.Len(t, named[2].MosaicNames, 0)
+}
@@ -22,6 +22,17 @@ func (ref *NamespaceService) GetNamespace(ctx context.Context, nsId *NamespaceId
 		return nil, ErrNilNamespaceId
 	}
 
+	nsInfo, err := ref.client.config.Cache.do(ctx, metadataKey{namespaceInfoKind, nsId.String()}, func(ctx context.Context) (interface{}, error) {
+		return ref.getNamespace(ctx, nsId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nsInfo.(*NamespaceInfo), nil
+}
+
+func (ref *NamespaceService) getNamespace(ctx context.Context, nsId *NamespaceId) (*NamespaceInfo, error) {
 	nsInfoDTO := &namespaceInfoDTO{}
 
 	url := net.NewUrl(fmt.Sprintf(namespaceRoute, nsId.toHexString()))
@@ -129,13 +140,85 @@ func (ref *NamespaceService) GetNamespacesFromAccounts(ctx context.Context, addr
 	return nsInfos, nil
 }
 
-// GetNamespaceNames return full info about Namespaces according to slice namespace ID
+// GetNamespaceNames return full info about Namespaces according to slice namespace ID,
+// in the order of nsIds, followed by the names returned for none of nsIds
 // @/namespace/names
+// Long lists are requested in chunks, see BatchConfig; if only some ids
+// fail, the other names are returned along with a *BatchError.
+// Names found in Config.Cache are not requested.
 func (ref *NamespaceService) GetNamespaceNames(ctx context.Context, nsIds []*NamespaceId) ([]*NamespaceName, error) {
 	if len(nsIds) == 0 {
 		return nil, ErrEmptyNamespaceIds
 	}
 
+	var (
+		cache   = ref.client.config.Cache
+		found   = make([]*NamespaceName, len(nsIds))
+		ids     = make([]string, 0, len(nsIds))
+		missing = make([]*NamespaceId, 0, len(nsIds))
+		pos     = make([]int, 0, len(nsIds))
+	)
+
+	for i, nsId := range nsIds {
+		if nsId == nil {
+			return nil, ErrNilNamespaceId
+		}
+
+		if v, ok := cache.get(metadataKey{namespaceNameKind, nsId.String()}); ok {
+			found[i] = v.(*NamespaceName)
+			continue
+		}
+
+		ids = append(ids, nsId.String())
+		missing = append(missing, nsId)
+		pos = append(pos, i)
+	}
+
+	unmatched := make([][]*NamespaceName, len(ids))
+
+	err := ref.client.doBatch(ctx, ids, func(ctx context.Context, offset int, ids []string) error {
+		nsNames, err := ref.getNamespaceNames(ctx, missing[offset:offset+len(ids)])
+		if err != nil {
+			return err
+		}
+
+		keys := make([][]string, len(nsNames))
+		for j, nsName := range nsNames {
+			if nsName.NamespaceId != nil {
+				keys[j] = []string{nsName.NamespaceId.String()}
+			}
+		}
+
+		byId, others := batchMatch(ids, keys)
+		for i, j := range byId {
+			if j >= 0 {
+				cache.set(metadataKey{namespaceNameKind, ids[i]}, nsNames[j])
+				found[pos[offset+i]] = nsNames[j]
+			}
+		}
+		for _, j := range others {
+			unmatched[offset] = append(unmatched[offset], nsNames[j])
+		}
+		return nil
+	})
+	if batchFailed(err) {
+		return nil, err
+	}
+
+	nsNames := make([]*NamespaceName, 0, len(found))
+	for _, nsName := range found {
+		if nsName != nil {
+			nsNames = append(nsNames, nsName)
+		}
+	}
+	for _, chunk := range unmatched {
+		nsNames = append(nsNames, chunk...)
+	}
+
+	return nsNames, err
+}
+
+func (ref *NamespaceService) getNamespaceNames(ctx context.Context, nsIds []*NamespaceId) ([]*NamespaceName, error) {
 	dtos := namespaceNameDTOs(make([]*namespaceNameDTO, 0))
 
 	resp, err := ref.client.DoNewRequest(ctx, http.MethodPost, namespaceNamesRoute, &NamespaceIds{nsIds}, &dtos)
@@ -150,6 +233,9 @@ func (ref *NamespaceService) GetNamespaceNames(ctx context.Context, nsIds []*Nam
 	return dtos.toStruct()
 }
 
+// buildNamespaceHierarchy links nsInfo, which must not be cached yet, to its
+// parent. GetNamespace returns the parent with its own hierarchy built, so
+// the cached parents are never modified.
 func (ref *NamespaceService) buildNamespaceHierarchy(ctx context.Context, nsInfo *NamespaceInfo) error {
 	if nsInfo == nil || nsInfo.Parent == nil {
 		return nil
@@ -166,7 +252,7 @@ func (ref *NamespaceService) buildNamespaceHierarchy(ctx context.Context, nsInfo
 
 	nsInfo.Parent = parentNsInfo
 
-	return ref.buildNamespaceHierarchy(ctx, nsInfo.Parent)
+	return nil
 }
 
 func (ref *NamespaceService) buildNamespacesHierarchy(ctx context.Context, nsInfos []*NamespaceInfo) error {
@@ -0,0 +1,319 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"io"
+	"sort"
+	"strings"
+)
+
+// NamespaceNode is a namespace of a tree returned by GetNamespaceTree.
+type NamespaceNode struct {
+	Info *NamespaceInfo
+	// Name is the name of the namespace, e.g. "bar".
+	Name string
+	// FullName is the name including the parents, e.g. "foo.bar".
+	FullName string
+	// Parent is nil for the root of the tree.
+	Parent *NamespaceNode
+	// Children are the sub-namespaces, sorted by name.
+	Children []*NamespaceNode
+	// Mosaics are the mosaics defined in the namespace.
+	Mosaics []*MosaicInfo
+}
+
+// Depth returns the level of the namespace, 1 for a root namespace.
+func (n *NamespaceNode) Depth() int {
+	return len(n.Info.Levels)
+}
+
+// Walk calls fn for the node and its descendants, parents before their
+// children. If fn returns SkipNamespace the children of the node are
+// skipped; any other error stops the walk and is returned.
+func (n *NamespaceNode) Walk(fn func(node *NamespaceNode) error) error {
+	if err := fn(n); err != nil {
+		if err == SkipNamespace {
+			return nil
+		}
+		return err
+	}
+
+	for _, child := range n.Children {
+		if err := child.Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find returns the namespace of the tree with the full name, e.g. "foo.bar",
+// or nil.
+func (n *NamespaceNode) Find(fullName string) *NamespaceNode {
+	fullName = strings.ToLower(fullName)
+
+	var found *NamespaceNode
+	n.Walk(func(node *NamespaceNode) error {
+		if found != nil || !strings.HasPrefix(fullName, node.FullName) {
+			return SkipNamespace
+		}
+		if node.FullName == fullName {
+			found = node
+		}
+		return nil
+	})
+
+	return found
+}
+
+// Print writes the tree with one namespace or mosaic per line, e.g.
+//
+//	foo (84b3552d375ffa4b)
+//	├── bar (d84b8b5e3fc8e4f2)
+//	│   └── foo.bar:coin (4c4ff1b0e6d5f0ab)
+//	└── baz (f1a9e9a7e9ac5a37)
+func (n *NamespaceNode) Print(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s (%s)\n", n.FullName, n.Info.NamespaceId.toHexString()); err != nil {
+		return err
+	}
+
+	return n.print(w, "")
+}
+
+func (n *NamespaceNode) print(w io.Writer, indent string) error {
+	count := len(n.Mosaics) + len(n.Children)
+
+	for i, m := range n.Mosaics {
+		branch := "├── "
+		if i == count-1 {
+			branch = "└── "
+		}
+
+		name := m.FullName
+		if name == "" {
+			name = m.MosaicId.toHexString()
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, name, m.MosaicId.toHexString()); err != nil {
+			return err
+		}
+	}
+
+	for i, child := range n.Children {
+		branch, next := "├── ", "│   "
+		if len(n.Mosaics)+i == count-1 {
+			branch, next = "└── ", "    "
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, child.Name, child.Info.NamespaceId.toHexString()); err != nil {
+			return err
+		}
+
+		if err := child.print(w, indent+next); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetNamespaceTree returns the namespace and its sub-namespaces down to the
+// leaves, each with its mosaics. The sub-namespaces are found among the
+// namespaces of the owner, which owns the whole tree, through their Levels.
+func (ref *NamespaceService) GetNamespaceTree(ctx context.Context, nsId *NamespaceId) (*NamespaceNode, error) {
+	nsInfo, err := ref.GetNamespace(ctx, nsId)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := NewAccountFromPublicKey(nsInfo.Owner.PublicKey, ref.client.config.NetworkType)
+	if err != nil {
+		return nil, err
+	}
+
+	nsInfos, err := ref.subNamespaces(ctx, owner.Address, nsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*NamespaceNode, len(nsInfos)+1)
+	nsIds := make([]*NamespaceId, 0, len(nsInfos)+len(nsInfo.Levels))
+
+	root := &NamespaceNode{Info: nsInfo}
+	nodes[nsInfo.NamespaceId.String()] = root
+	nsIds = append(nsIds, nsInfo.Levels...)
+
+	for _, sub := range nsInfos {
+		nodes[sub.NamespaceId.String()] = &NamespaceNode{Info: sub}
+		nsIds = append(nsIds, sub.NamespaceId)
+	}
+
+	// the parent of a namespace is the level above its own
+	for _, sub := range nsInfos {
+		node := nodes[sub.NamespaceId.String()]
+		parent, ok := nodes[sub.Levels[len(sub.Levels)-2].String()]
+		if !ok {
+			continue
+		}
+
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	if err := ref.nameTree(ctx, root, nsIds); err != nil {
+		return nil, err
+	}
+
+	if err := ref.client.Mosaic.attachMosaics(ctx, root); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// subNamespaces pages through the namespaces of owner and returns those
+// below nsInfo.
+func (ref *NamespaceService) subNamespaces(ctx context.Context, owner *Address, nsInfo *NamespaceInfo) ([]*NamespaceInfo, error) {
+	subs := make([]*NamespaceInfo, 0)
+	seen := make(map[string]bool)
+
+	var last *NamespaceId
+	for {
+		page, err := ref.GetNamespacesFromAccount(ctx, owner, last, defaultNamespacesPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, sub := range page {
+			if sub.NamespaceId == nil || seen[sub.NamespaceId.String()] {
+				continue
+			}
+
+			seen[sub.NamespaceId.String()] = true
+			added++
+
+			if isSubNamespace(sub, nsInfo) {
+				subs = append(subs, sub)
+			}
+		}
+
+		if len(page) < defaultNamespacesPageSize || added == 0 {
+			return subs, nil
+		}
+
+		last = page[len(page)-1].NamespaceId
+	}
+}
+
+// isSubNamespace reports whether the levels of nsInfo start with those of
+// parent.
+func isSubNamespace(nsInfo *NamespaceInfo, parent *NamespaceInfo) bool {
+	if len(parent.Levels) == 0 || len(nsInfo.Levels) <= len(parent.Levels) {
+		return false
+	}
+
+	for i, level := range parent.Levels {
+		if namespaceIdToBigInt(level).Cmp(namespaceIdToBigInt(nsInfo.Levels[i])) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nameTree sets the names of the nodes and sorts the children by name.
+func (ref *NamespaceService) nameTree(ctx context.Context, root *NamespaceNode, nsIds []*NamespaceId) error {
+	nsNames, err := ref.GetNamespaceNames(ctx, nsIds)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]string, len(nsNames))
+	for _, nsName := range nsNames {
+		names[nsName.NamespaceId.String()] = nsName.Name
+	}
+
+	return root.Walk(func(node *NamespaceNode) error {
+		levels := node.Info.Levels
+		node.Name = names[levels[len(levels)-1].String()]
+
+		if node.Parent == nil {
+			parts := make([]string, len(levels))
+			for i, level := range levels {
+				parts[i] = names[level.String()]
+			}
+			node.FullName = strings.Join(parts, ".")
+		} else {
+			node.FullName = node.Parent.FullName + "." + node.Name
+		}
+
+		sort.Slice(node.Children, func(i, j int) bool {
+			return names[node.Children[i].Info.NamespaceId.String()] < names[node.Children[j].Info.NamespaceId.String()]
+		})
+		return nil
+	})
+}
+
+// attachMosaics sets the mosaics of the nodes, with full names such as
+// "foo.bar:coin".
+func (ref *MosaicService) attachMosaics(ctx context.Context, root *NamespaceNode) error {
+	mscIds := make([]*MosaicId, 0)
+
+	err := root.Walk(func(node *NamespaceNode) error {
+		seen := make(map[string]bool)
+
+		var last *MosaicId
+		for {
+			page, err := ref.GetMosaicsFromNamespaceUpToMosaic(ctx, node.Info.NamespaceId, last, defaultNamespacesPageSize)
+			if err != nil {
+				return err
+			}
+
+			added := 0
+			for _, mscInfo := range page {
+				if mscInfo.MosaicId == nil || seen[mscInfo.MosaicId.String()] {
+					continue
+				}
+
+				seen[mscInfo.MosaicId.String()] = true
+				node.Mosaics = append(node.Mosaics, mscInfo)
+				mscIds = append(mscIds, mscInfo.MosaicId)
+				added++
+			}
+
+			if len(page) < defaultNamespacesPageSize || added == 0 {
+				return nil
+			}
+
+			last = page[len(page)-1].MosaicId
+		}
+	})
+	if err != nil || len(mscIds) == 0 {
+		return err
+	}
+
+	mscNames, err := ref.GetMosaicNames(ctx, mscIds)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]string, len(mscNames))
+	for _, mscName := range mscNames {
+		names[mscName.MosaicId.String()] = mscName.Name
+	}
+
+	return root.Walk(func(node *NamespaceNode) error {
+		for _, mscInfo := range node.Mosaics {
+			if name, ok := names[mscInfo.MosaicId.String()]; ok {
+				mscInfo.FullName = node.FullName + ":" + name
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceService_GetNamespaceTree(t *testing.T) {
+	// foo (1) with foo.bar (2) and foo.baz (3), and the unrelated root qux (5)
+	infos := map[uint64]string{
+		1: testNamespaceJSON(1, 0, "[6000, 0]"),
+		2: testNamespaceJSON(2, 1, "[6000, 0]"),
+		3: testNamespaceJSON(3, 1, "[6000, 0]"),
+		5: testNamespaceJSON(5, 0, "[6000, 0]"),
+	}
+	names := map[uint64]string{1: "foo", 2: "bar", 3: "baz", 5: "qux"}
+
+	// coin is defined in foo.bar
+	coin := strings.Replace(tplMosaic, "929036875,\n      2226345261", "2,\n      0", 1)
+
+	owner, err := NewAccountFromPublicKey("321DE652C4D3362FC2DDF7800F6582F4A10CFEA134B81F8AB6E4BE78BBA4D18E", TestNet)
+	assert.Nil(t, err)
+
+	hex := func(id uint64) string {
+		return BigIntegerToHex(new(big.Int).SetUint64(id))
+	}
+
+	nsNames := make([]string, 0, len(names))
+	for id, name := range names {
+		nsNames = append(nsNames, fmt.Sprintf(`{"namespaceId": [%d, 0], "name": "%s", "parentId": [0, 0]}`, id, name))
+	}
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(namespacesFromAccountRoutes, owner.Address.Address),
+		RespBody: fmt.Sprintf("[%s,%s,%s,%s]", infos[1], infos[3], infos[5], infos[2]),
+	})
+	defer m.Close()
+
+	m.AddRouter(&mock.Router{
+		Path:     namespaceNamesRoute,
+		RespBody: "[" + strings.Join(nsNames, ",") + "]",
+	})
+	m.AddRouter(&mock.Router{
+		Path:     mosaicNamesRoute,
+		RespBody: `[{"mosaicId": [3646934825, 3576016193], "name": "coin", "parentId": [2, 0]}]`,
+	})
+
+	for id, info := range infos {
+		mosaics := "[]"
+		if id == 2 {
+			mosaics = "[" + coin + "]"
+		}
+
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(namespaceRoute, hex(id)),
+			RespBody: info,
+		})
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(mosaicsFromNamespaceRoute, hex(id)),
+			RespBody: mosaics,
+		})
+	}
+
+	client := m.getTestNetClientUnsafe()
+
+	root, err := client.Namespace.GetNamespaceTree(ctx, bigIntToNamespaceId(big.NewInt(1)))
+	assert.Nilf(t, err, "NamespaceService.GetNamespaceTree returned error: %s", err)
+
+	assert.Equal(t, "foo", root.FullName)
+	assert.Equal(t, 1, root.Depth())
+	assert.Len(t, root.Children, 2)
+	assert.Len(t, root.Mosaics, 0)
+
+	bar := root.Find("FOO.bar")
+	assert.NotNil(t, bar)
+	assert.Equal(t, "bar", bar.Name)
+	assert.Equal(t, root, bar.Parent)
+	assert.Equal(t, 2, bar.Depth())
+	assert.Len(t, bar.Mosaics, 1)
+	assert.Equal(t, "foo.bar:coin", bar.Mosaics[0].FullName)
+
+	assert.Nil(t, root.Find("qux"))
+	assert.Nil(t, root.Find("foo.bar.coin"))
+
+	var b bytes.Buffer
+	assert.Nil(t, root.Print(&b))
+	assert.Equal(t, fmt.Sprintf(`foo (%s)
+├── bar (%s)
+│   └── foo.bar:coin (%s)
+└── baz (%s)
+`, hex(1), hex(2), testMosaicPathID, hex(3)), b.String())
+
+	visited := make([]string, 0)
+	err = root.Walk(func(node *NamespaceNode) error {
+		visited = append(visited, node.FullName)
+		if node.Name == "bar" {
+			return SkipNamespace
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo", "foo.bar", "foo.baz"}, visited)
+
+	errStop := errors.New("stop")
+	assert.Equal(t, errStop, root.Walk(func(node *NamespaceNode) error {
+		return errStop
+	}))
+}
@@ -0,0 +1,525 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ExpectedPayment is a deposit identified by the message of the transfers
+// paying it.
+type ExpectedPayment struct {
+	// Reference is the message payload of the transfers, compared without
+	// surrounding spaces.
+	Reference string
+	MosaicId  *MosaicId
+	// Amount is the minimum amount of the mosaic, in its smallest unit.
+	Amount *big.Int
+	// Received is the amount already received before the payment is
+	// registered, e.g. restored from the events of a previous run.
+	Received *big.Int
+}
+
+// PaymentEventType tells how a transfer relates to the expected payments.
+type PaymentEventType uint8
+
+const (
+	// PaymentPartial is a transfer which brings a payment closer to its
+	// Amount without reaching it.
+	PaymentPartial PaymentEventType = iota
+	// PaymentMatched is a transfer which brings a payment to exactly its Amount.
+	PaymentMatched
+	// PaymentOverpaid is a transfer which brings a payment over its Amount,
+	// including any transfer to a payment already matched.
+	PaymentOverpaid
+	// PaymentUnmatched is a transfer of an unknown reference or of another
+	// mosaic than the one expected.
+	PaymentUnmatched
+)
+
+func (t PaymentEventType) String() string {
+	switch t {
+	case PaymentPartial:
+		return "partial"
+	case PaymentMatched:
+		return "matched"
+	case PaymentOverpaid:
+		return "overpaid"
+	case PaymentUnmatched:
+		return "unmatched"
+	}
+	return "unknown"
+}
+
+// PaymentEvent reports one mosaic of an incoming transfer.
+type PaymentEvent struct {
+	Type PaymentEventType
+	// Payment is the expected payment, nil for an unknown reference.
+	Payment *ExpectedPayment
+	// Hash is the hash of the transaction, or of its aggregate for inner
+	// transactions.
+	Hash        Hash
+	Height      *big.Int
+	Transaction *TransferTransaction
+	Mosaic      *Mosaic
+	// Total is the amount received for Payment so far, this transfer included.
+	Total *big.Int
+}
+
+// PaymentCheckpoint is the progress of a PaymentDetector. Transactions may
+// be received out of order, so the transactions processed in the last blocks
+// up to Height are kept by hash, see PaymentDetectorOptions.Window.
+type PaymentCheckpoint struct {
+	// Height is the highest height of a processed transaction.
+	Height *big.Int `json:"height"`
+	// Processed maps the hashes of the transactions processed in the window
+	// to their height.
+	Processed map[Hash]*big.Int `json:"processed"`
+	// Received is the amount received for each expected payment, by reference.
+	Received map[string]*big.Int `json:"received"`
+}
+
+func (cp *PaymentCheckpoint) copy() *PaymentCheckpoint {
+	c := &PaymentCheckpoint{
+		Height:    new(big.Int).Set(cp.Height),
+		Processed: make(map[Hash]*big.Int, len(cp.Processed)),
+		Received:  make(map[string]*big.Int, len(cp.Received)),
+	}
+	for hash, height := range cp.Processed {
+		c.Processed[hash] = new(big.Int).Set(height)
+	}
+	for ref, amount := range cp.Received {
+		c.Received[ref] = new(big.Int).Set(amount)
+	}
+	return c
+}
+
+// PaymentCheckpointStore persists the checkpoint of a PaymentDetector.
+type PaymentCheckpointStore interface {
+	// Load returns the saved checkpoint, nil if there is none.
+	Load() (*PaymentCheckpoint, error)
+	Save(checkpoint *PaymentCheckpoint) error
+}
+
+// FilePaymentCheckpointStore keeps the checkpoint in a JSON file.
+type FilePaymentCheckpointStore struct {
+	Path string
+}
+
+// Load reads the checkpoint from the file; a missing file means no checkpoint.
+func (s *FilePaymentCheckpointStore) Load() (*PaymentCheckpoint, error) {
+	b, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &PaymentCheckpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Save writes the checkpoint to a temporary file renamed over the file, so a
+// crash never leaves it half written.
+func (s *FilePaymentCheckpointStore) Save(checkpoint *PaymentCheckpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
+
+// PaymentDetectorOptions configures a PaymentDetector.
+type PaymentDetectorOptions struct {
+	// OnEvent is called for every mosaic of every incoming transfer.
+	OnEvent func(event *PaymentEvent)
+	// Checkpoint persists the progress, so a restarted detector resumes
+	// where it stopped. Without it a detector starts from the first block.
+	Checkpoint PaymentCheckpointStore
+	// PageSize is the number of transactions requested at a time while
+	// catching up, 100 by default.
+	PageSize int
+	// Window is the number of blocks below the checkpoint height whose
+	// transactions are remembered, so they are processed once whatever order
+	// they arrive in, and requested again by CatchUp, 100 by default.
+	// Transactions further below are ignored.
+	Window int
+}
+
+const defaultPaymentWindow = 100
+
+// PaymentDetector matches the transfers received by an account with the
+// expected payments registered by Expect.
+//
+// Every transaction is processed once: transactions received again, e.g.
+// through both the websocket and CatchUp, are ignored. The checkpoint, which
+// holds the amounts received, is saved after the events of a transaction,
+// so only a crash in between can repeat them; their Hash tells them apart.
+type PaymentDetector struct {
+	client  *Client
+	account *PublicAccount
+	opts    PaymentDetectorOptions
+
+	mu         sync.Mutex
+	payments   map[string]*ExpectedPayment
+	checkpoint *PaymentCheckpoint
+}
+
+// NewPaymentDetector returns a detector of the payments to account, resumed
+// from the checkpoint of opts if any.
+func NewPaymentDetector(client *Client, account *PublicAccount, opts *PaymentDetectorOptions) (*PaymentDetector, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+	if account == nil || account.Address == nil {
+		return nil, ErrNilAccount
+	}
+
+	d := &PaymentDetector{
+		client:   client,
+		account:  account,
+		payments: make(map[string]*ExpectedPayment),
+		checkpoint: &PaymentCheckpoint{
+			Height:    big.NewInt(0),
+			Processed: make(map[Hash]*big.Int),
+			Received:  make(map[string]*big.Int),
+		},
+	}
+
+	if opts != nil {
+		d.opts = *opts
+	}
+	if d.opts.PageSize <= 0 {
+		d.opts.PageSize = defaultHistoryPageSize
+	}
+	if d.opts.Window <= 0 {
+		d.opts.Window = defaultPaymentWindow
+	}
+
+	if d.opts.Checkpoint != nil {
+		cp, err := d.opts.Checkpoint.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		if cp != nil && cp.Height != nil {
+			d.checkpoint.Height = cp.Height
+			for hash, height := range cp.Processed {
+				if height != nil {
+					d.checkpoint.Processed[hash] = height
+				}
+			}
+			for ref, amount := range cp.Received {
+				if amount != nil {
+					d.checkpoint.Received[ref] = amount
+				}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// Expect registers a payment. Registering a reference again, or after a
+// restart, replaces the payment, keeping what was received for it unless
+// payment.Received is set.
+func (d *PaymentDetector) Expect(payment *ExpectedPayment) error {
+	if payment == nil || payment.Amount == nil {
+		return ErrNilMosaicAmount
+	}
+	if payment.MosaicId == nil {
+		return ErrNilMosaicId
+	}
+
+	ref := strings.TrimSpace(payment.Reference)
+	if ref == "" {
+		return ErrBlankPaymentReference
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.payments[ref] = payment
+	if payment.Received != nil {
+		d.checkpoint.Received[ref] = new(big.Int).Set(payment.Received)
+	} else if d.checkpoint.Received[ref] == nil {
+		d.checkpoint.Received[ref] = big.NewInt(0)
+	}
+	return nil
+}
+
+// Forget removes the payment of reference, whose transfers then become
+// unmatched.
+func (d *PaymentDetector) Forget(reference string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ref := strings.TrimSpace(reference)
+	delete(d.payments, ref)
+	delete(d.checkpoint.Received, ref)
+}
+
+// Received returns the amount received for the payment of reference, nil if
+// it is not registered.
+func (d *PaymentDetector) Received(reference string) *big.Int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if r, ok := d.checkpoint.Received[strings.TrimSpace(reference)]; ok {
+		return new(big.Int).Set(r)
+	}
+	return nil
+}
+
+// Checkpoint returns a copy of the current checkpoint.
+func (d *PaymentDetector) Checkpoint() *PaymentCheckpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.checkpoint.copy()
+}
+
+// WatchWebsocket subscribes to the confirmed transactions of the account,
+// catches up with the ones confirmed since the checkpoint and then processes
+// the subscription until the context is done. The subscription starts first,
+// so no transaction confirmed meanwhile is missed.
+func (d *PaymentDetector) WatchWebsocket(ctx context.Context, ws *ClientWebsocket) error {
+	sub, err := ws.Subscribe.ConfirmedAdded(d.account.Address)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	if err := d.CatchUp(ctx); err != nil {
+		return err
+	}
+
+	return d.Watch(ctx, sub.Ch)
+}
+
+// Watch processes transactions until the context is done or the channel is closed.
+func (d *PaymentDetector) Watch(ctx context.Context, txs <-chan Transaction) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case tx, ok := <-txs:
+			if !ok {
+				return nil
+			}
+
+			if err := d.OnTransaction(tx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CatchUp processes the incoming transactions confirmed since the start of
+// the checkpoint window, oldest first.
+func (d *PaymentDetector) CatchUp(ctx context.Context) error {
+	d.mu.Lock()
+	from := d.windowStart()
+	d.mu.Unlock()
+
+	pending := make([]Transaction, 0)
+	opt := &AccountTransactionsOption{PageSize: d.opts.PageSize}
+
+	// pages come newest first
+	for done := false; !done; {
+		txs, err := d.client.Account.IncomingTransactions(ctx, d.account, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			info := tx.GetAbstractTransaction().TransactionInfo
+			if info == nil || info.Height == nil {
+				continue
+			}
+			if info.Height.Cmp(from) < 0 {
+				done = true
+				break
+			}
+			pending = append(pending, tx)
+		}
+
+		if len(txs) < d.opts.PageSize {
+			break
+		}
+
+		info := txs[len(txs)-1].GetAbstractTransaction().TransactionInfo
+		if info == nil || info.Id == "" {
+			break
+		}
+		opt = &AccountTransactionsOption{PageSize: d.opts.PageSize, Id: info.Id}
+	}
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		if err := d.OnTransaction(pending[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnTransaction processes a confirmed transaction, emitting an event for
+// every mosaic of the transfers it holds to the account, and advances the
+// checkpoint. Unconfirmed transactions, transactions processed already and
+// transactions below the checkpoint window are ignored.
+func (d *PaymentDetector) OnTransaction(tx Transaction) error {
+	if tx == nil {
+		return nil
+	}
+
+	info := tx.GetAbstractTransaction().TransactionInfo
+	if info == nil || info.Height == nil || info.Height.Sign() == 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+
+	if _, ok := d.checkpoint.Processed[info.Hash]; ok || info.Height.Cmp(d.windowStart()) < 0 {
+		d.mu.Unlock()
+		return nil
+	}
+
+	transfers := make([]*TransferTransaction, 0)
+	switch t := tx.(type) {
+	case *TransferTransaction:
+		transfers = append(transfers, t)
+	case *AggregateTransaction:
+		for _, inner := range t.InnerTransactions {
+			if ttx, ok := inner.(*TransferTransaction); ok {
+				transfers = append(transfers, ttx)
+			}
+		}
+	}
+
+	events := make([]*PaymentEvent, 0)
+	for _, ttx := range transfers {
+		if !d.account.Address.Equals(ttx.Recipient) {
+			continue
+		}
+
+		for _, m := range ttx.Mosaics {
+			events = append(events, d.match(info, ttx, m))
+		}
+	}
+
+	checkpoint := d.advance(info)
+	d.mu.Unlock()
+
+	// OnEvent may call the detector, so it is called without the lock
+	if d.opts.OnEvent != nil {
+		for _, event := range events {
+			d.opts.OnEvent(event)
+		}
+	}
+
+	if d.opts.Checkpoint == nil {
+		return nil
+	}
+	return d.opts.Checkpoint.Save(checkpoint)
+}
+
+func (d *PaymentDetector) match(info *TransactionInfo, tx *TransferTransaction, mosaic *Mosaic) *PaymentEvent {
+	event := &PaymentEvent{
+		Type:        PaymentUnmatched,
+		Hash:        info.Hash,
+		Height:      info.Height,
+		Transaction: tx,
+		Mosaic:      mosaic,
+	}
+
+	var ref string
+	if tx.Message != nil {
+		ref = strings.TrimSpace(tx.Message.Payload)
+	}
+
+	payment, ok := d.payments[ref]
+	if !ok {
+		return event
+	}
+	event.Payment = payment
+
+	if mosaicIdToBigInt(payment.MosaicId).Cmp(mosaicIdToBigInt(mosaic.MosaicId)) != 0 {
+		return event
+	}
+
+	total := new(big.Int).Add(d.checkpoint.Received[ref], mosaic.Amount)
+	d.checkpoint.Received[ref] = total
+	event.Total = new(big.Int).Set(total)
+
+	switch total.Cmp(payment.Amount) {
+	case -1:
+		event.Type = PaymentPartial
+	case 0:
+		event.Type = PaymentMatched
+	default:
+		event.Type = PaymentOverpaid
+	}
+
+	return event
+}
+
+// advance adds the transaction to the checkpoint, forgets the transactions
+// which left the window and returns a copy of the checkpoint.
+func (d *PaymentDetector) advance(info *TransactionInfo) *PaymentCheckpoint {
+	d.checkpoint.Processed[info.Hash] = new(big.Int).Set(info.Height)
+
+	if info.Height.Cmp(d.checkpoint.Height) > 0 {
+		d.checkpoint.Height = new(big.Int).Set(info.Height)
+
+		start := d.windowStart()
+		for hash, height := range d.checkpoint.Processed {
+			if height.Cmp(start) < 0 {
+				delete(d.checkpoint.Processed, hash)
+			}
+		}
+	}
+
+	return d.checkpoint.copy()
+}
+
+// windowStart returns the lowest height of the checkpoint window.
+func (d *PaymentDetector) windowStart() *big.Int {
+	start := new(big.Int).Sub(d.checkpoint.Height, big.NewInt(int64(d.opts.Window)))
+	if start.Sign() < 0 {
+		start.SetInt64(0)
+	}
+	return start
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func paymentTestTransfer(height int64, hash string, recipient *Address, reference string, mosaics ...*Mosaic) *TransferTransaction {
+	return &TransferTransaction{
+		AbstractTransaction: AbstractTransaction{
+			TransactionInfo: &TransactionInfo{Height: big.NewInt(height), Hash: Hash(hash)},
+			Type:            Transfer,
+		},
+		Message:   NewPlainMessage(reference),
+		Mosaics:   mosaics,
+		Recipient: recipient,
+	}
+}
+
+type paymentTestEvents []*PaymentEvent
+
+func (e *paymentTestEvents) add(event *PaymentEvent) {
+	*e = append(*e, event)
+}
+
+func (e paymentTestEvents) types() []PaymentEventType {
+	types := make([]PaymentEventType, len(e))
+	for i, event := range e {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestPaymentDetector_OnTransaction(t *testing.T) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+	other, err := NewAccountFromPublicKey(testNEMPublicKey, TestNet)
+	assert.Nil(t, err)
+
+	events := &paymentTestEvents{}
+	d, err := NewPaymentDetector(mockServer.getTestNetClientUnsafe(), account, &PaymentDetectorOptions{OnEvent: events.add})
+	assert.Nil(t, err)
+
+	assert.Nil(t, d.Expect(&ExpectedPayment{Reference: "order-1", MosaicId: XemMosaicId, Amount: big.NewInt(100)}))
+	assert.Nil(t, d.Expect(&ExpectedPayment{Reference: "order-2", MosaicId: XemMosaicId, Amount: big.NewInt(100), Received: big.NewInt(60)}))
+	assert.Equal(t, ErrBlankPaymentReference, d.Expect(&ExpectedPayment{Reference: " ", MosaicId: XemMosaicId, Amount: big.NewInt(1)}))
+
+	for _, tx := range []Transaction{
+		paymentTestTransfer(10, "A1", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(40)}),
+		// received again, e.g. through the websocket after CatchUp
+		paymentTestTransfer(10, "A1", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(40)}),
+		paymentTestTransfer(11, "A2", account.Address, " order-1 ", &Mosaic{XemMosaicId, big.NewInt(60)}, &Mosaic{testMosaicIds[1], big.NewInt(5)}),
+		paymentTestTransfer(11, "A3", other.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(60)}),
+		paymentTestTransfer(12, "A4", account.Address, "order-2", &Mosaic{XemMosaicId, big.NewInt(50)}),
+		paymentTestTransfer(12, "A5", account.Address, "unknown", &Mosaic{XemMosaicId, big.NewInt(1)}),
+		paymentTestTransfer(13, "A6", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(1)}),
+		// below the checkpoint height, but within the window
+		paymentTestTransfer(12, "A7", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(1)}),
+		paymentTestTransfer(10, "A1", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(40)}),
+	} {
+		assert.Nil(t, d.OnTransaction(tx))
+	}
+
+	assert.Equal(t, []PaymentEventType{
+		PaymentPartial,
+		PaymentMatched, PaymentUnmatched,
+		PaymentOverpaid,
+		PaymentUnmatched,
+		PaymentOverpaid,
+		PaymentOverpaid,
+	}, events.types())
+
+	assert.Equal(t, big.NewInt(40), (*events)[0].Total)
+	assert.Equal(t, Hash("A2"), (*events)[2].Hash)
+	assert.Equal(t, "order-1", (*events)[2].Payment.Reference)
+	assert.Nil(t, (*events)[4].Payment)
+	assert.Equal(t, big.NewInt(110), (*events)[3].Total)
+	assert.Equal(t, Hash("A7"), (*events)[6].Hash)
+
+	assert.Equal(t, big.NewInt(102), d.Received("order-1"))
+
+	cp := d.Checkpoint()
+	assert.Equal(t, big.NewInt(13), cp.Height)
+	assert.Len(t, cp.Processed, 7)
+	assert.Equal(t, big.NewInt(12), cp.Processed["A7"])
+	assert.Equal(t, big.NewInt(110), cp.Received["order-2"])
+
+	d.Forget("order-1")
+	assert.Nil(t, d.Received("order-1"))
+}
+
+func TestPaymentDetector_OnTransactionWindow(t *testing.T) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+
+	events := &paymentTestEvents{}
+	d, err := NewPaymentDetector(mockServer.getTestNetClientUnsafe(), account, &PaymentDetectorOptions{OnEvent: events.add, Window: 2})
+	assert.Nil(t, err)
+	assert.Nil(t, d.Expect(&ExpectedPayment{Reference: "order-1", MosaicId: XemMosaicId, Amount: big.NewInt(100)}))
+
+	for _, tx := range []Transaction{
+		paymentTestTransfer(10, "B1", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(10)}),
+		paymentTestTransfer(13, "B2", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(20)}),
+		// below the window of blocks 11 to 13
+		paymentTestTransfer(10, "B3", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(40)}),
+		paymentTestTransfer(11, "B4", account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(30)}),
+	} {
+		assert.Nil(t, d.OnTransaction(tx))
+	}
+
+	assert.Len(t, *events, 3)
+	assert.Equal(t, big.NewInt(60), d.Received("order-1"))
+
+	// the transactions which left the window are forgotten
+	cp := d.Checkpoint()
+	assert.Len(t, cp.Processed, 2)
+	assert.Nil(t, cp.Processed["B1"])
+}
+
+func TestPaymentDetector_CatchUp(t *testing.T) {
+	account, err := NewAccountFromPublicKey(publicKey1, TestNet)
+	assert.Nil(t, err)
+
+	// incoming transactions, newest first
+	txs := []string{
+		testTransferJSON(t, 30, testNEMPublicKey, account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(70)}),
+		testTransferJSON(t, 20, testNEMPublicKey, account.Address, "order-2", &Mosaic{XemMosaicId, big.NewInt(10)}),
+		testTransferJSON(t, 10, testNEMPublicKey, account.Address, "order-1", &Mosaic{XemMosaicId, big.NewInt(30)}),
+	}
+
+	m := newSdkMockWithRouter(&mock.Router{
+		Path:     fmt.Sprintf(transactionsByAccountRoute, account.PublicKey, incomingTransactionsRoute),
+		RespBody: "[" + strings.Join(txs, ",") + "]",
+	})
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "payment")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store := &FilePaymentCheckpointStore{Path: filepath.Join(dir, "checkpoint.json")}
+	client := m.getTestNetClientUnsafe()
+
+	events := &paymentTestEvents{}
+	d, err := NewPaymentDetector(client, account, &PaymentDetectorOptions{OnEvent: events.add, Checkpoint: store})
+	assert.Nil(t, err)
+	assert.Nil(t, d.Expect(&ExpectedPayment{Reference: "order-1", MosaicId: XemMosaicId, Amount: big.NewInt(100)}))
+
+	assert.Nil(t, d.CatchUp(ctx))
+	assert.Equal(t, []PaymentEventType{PaymentPartial, PaymentUnmatched, PaymentMatched}, events.types())
+	assert.Equal(t, big.NewInt(10), (*events)[0].Height)
+
+	cp, err := store.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "30", cp.Height.String())
+	assert.Len(t, cp.Processed, 3)
+	assert.Equal(t, "100", cp.Received["order-1"].String())
+
+	// a restarted detector resumes from the checkpoint, with what was received
+	events = &paymentTestEvents{}
+	d, err = NewPaymentDetector(client, account, &PaymentDetectorOptions{OnEvent: events.add, Checkpoint: store})
+	assert.Nil(t, err)
+	assert.Nil(t, d.Expect(&ExpectedPayment{Reference: "order-1", MosaicId: XemMosaicId, Amount: big.NewInt(100)}))
+
+	assert.Nil(t, d.CatchUp(ctx))
+	assert.Len(t, *events, 0)
+	assert.Equal(t, "100", d.Received("order-1").String())
+}
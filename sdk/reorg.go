@@ -0,0 +1,214 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// defaultReorgDepth is the maximum number of blocks Catapult rolls back
+const defaultReorgDepth = 360
+
+// ReorgOptions configures a ReorgDetector
+type ReorgOptions struct {
+	// Depth is the number of recent blocks kept to find a fork point, 360 by default
+	Depth int
+	// OnReorg is called when the chain switched to a fork
+	OnReorg func(reorg *Reorg)
+	// OnError is called by Watch with the error of a block, such as ErrReorgTooDeep or a failed
+	// GetBlockByHeight; the next blocks are watched anyway
+	OnError func(err error)
+}
+
+// Reorg describes a switch of the chain to a fork
+type Reorg struct {
+	// ForkHeight is the height of the last block common to both forks
+	ForkHeight *big.Int
+	// Orphaned are the blocks seen above ForkHeight which are no longer part of the chain, lowest first
+	Orphaned []*BlockInfo
+	// NewBlocks replace the orphaned blocks, lowest first, up to the block which revealed the fork
+	NewBlocks []*BlockInfo
+}
+
+// ReorgDetector checks that every new block follows the previous one and reports chain reorganizations.
+// It is safe for concurrent use; blocks are checked one at a time.
+type ReorgDetector struct {
+	client *Client
+	opts   ReorgOptions
+
+	mu sync.Mutex
+	// recent blocks of the chain, lowest first and without gaps
+	blocks []*BlockInfo
+}
+
+// NewReorgDetector returns a detector using client to walk back to fork points
+func NewReorgDetector(client *Client, opts *ReorgOptions) (*ReorgDetector, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	d := &ReorgDetector{client: client}
+
+	if opts != nil {
+		d.opts = *opts
+	}
+
+	if d.opts.Depth <= 0 {
+		d.opts.Depth = defaultReorgDepth
+	}
+
+	return d, nil
+}
+
+// WatchWebsocket subscribes to new blocks and watches them until the context is done
+func (d *ReorgDetector) WatchWebsocket(ctx context.Context, ws *ClientWebsocket) error {
+	sub, err := ws.Subscribe.Block()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	return d.Watch(ctx, sub.Ch)
+}
+
+// Watch processes blocks until the context is done or the channel is closed. The error of a block
+// is reported to OnError and does not stop the watch
+func (d *ReorgDetector) Watch(ctx context.Context, blocks <-chan *BlockInfo) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case block, ok := <-blocks:
+			if !ok {
+				return nil
+			}
+
+			if _, err := d.OnBlock(ctx, block); err != nil && ctx.Err() == nil && d.opts.OnError != nil {
+				d.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// OnBlock checks the PreviousBlockHash of a new block against the last block seen.
+// When they do not match it walks back with GetBlockByHeight to the fork point and
+// returns the reorganization, which is also passed to OnReorg. Blocks skipped since
+// the last block seen are requested and checked as well. Blocks seen already, or older
+// than the blocks kept, are ignored.
+// ErrReorgTooDeep is returned when the fork point is older than the Depth blocks kept;
+// the detector then starts over from the new block. When a request fails the blocks kept are
+// left as they were, so the walk is done again with the next block.
+func (d *ReorgDetector) OnBlock(ctx context.Context, block *BlockInfo) (*Reorg, error) {
+	d.mu.Lock()
+	reorg, err := d.onBlock(ctx, block)
+	d.mu.Unlock()
+
+	// OnReorg is called unlocked, so that it may call OnBlock
+	if reorg != nil && d.opts.OnReorg != nil {
+		d.opts.OnReorg(reorg)
+	}
+
+	return reorg, err
+}
+
+func (d *ReorgDetector) onBlock(ctx context.Context, block *BlockInfo) (*Reorg, error) {
+	if block == nil || block.Height == nil {
+		return nil, nil
+	}
+
+	if len(d.blocks) == 0 {
+		d.push(block)
+		return nil, nil
+	}
+
+	// blocks older than the blocks kept can not be checked
+	if block.Height.Cmp(d.blocks[0].Height) < 0 {
+		return nil, nil
+	}
+
+	if seen := d.at(block.Height); seen != nil && strings.EqualFold(seen.Hash, block.Hash) {
+		return nil, nil
+	}
+
+	last := d.blocks[len(d.blocks)-1]
+	if new(big.Int).Sub(block.Height, last.Height).Int64() == 1 && strings.EqualFold(block.PreviousBlockHash, last.Hash) {
+		d.push(block)
+		return nil, nil
+	}
+
+	newBlocks := []*BlockInfo{block}
+	cur := block
+
+	for {
+		height := new(big.Int).Sub(cur.Height, big.NewInt(1))
+
+		if height.Cmp(d.blocks[0].Height) < 0 || height.Sign() == 0 {
+			d.blocks = nil
+			for _, b := range newBlocks {
+				d.push(b)
+			}
+			return nil, ErrReorgTooDeep
+		}
+
+		if seen := d.at(height); seen != nil && strings.EqualFold(seen.Hash, cur.PreviousBlockHash) {
+			break
+		}
+
+		prev, err := d.client.Blockchain.GetBlockByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+
+		newBlocks = append([]*BlockInfo{prev}, newBlocks...)
+		cur = prev
+	}
+
+	forkHeight := new(big.Int).Sub(cur.Height, big.NewInt(1))
+	keep := new(big.Int).Sub(forkHeight, d.blocks[0].Height).Int64() + 1
+
+	orphaned := append([]*BlockInfo{}, d.blocks[keep:]...)
+	d.blocks = d.blocks[:keep]
+	for _, b := range newBlocks {
+		d.push(b)
+	}
+
+	// the walk only filled a gap
+	if len(orphaned) == 0 {
+		return nil, nil
+	}
+
+	return &Reorg{
+		ForkHeight: forkHeight,
+		Orphaned:   orphaned,
+		NewBlocks:  newBlocks,
+	}, nil
+}
+
+// push appends the block following the last one, dropping the oldest blocks beyond Depth
+func (d *ReorgDetector) push(block *BlockInfo) {
+	d.blocks = append(d.blocks, block)
+
+	if len(d.blocks) > d.opts.Depth {
+		d.blocks = append([]*BlockInfo{}, d.blocks[len(d.blocks)-d.opts.Depth:]...)
+	}
+}
+
+// at returns the block seen at height, or nil
+func (d *ReorgDetector) at(height *big.Int) *BlockInfo {
+	if len(d.blocks) == 0 {
+		return nil
+	}
+
+	i := new(big.Int).Sub(height, d.blocks[0].Height)
+	if i.Sign() < 0 || i.Cmp(big.NewInt(int64(len(d.blocks)))) >= 0 {
+		return nil
+	}
+
+	return d.blocks[i.Int64()]
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"fmt"
+	"github.com/proximax-storage/proximax-utils-go/mock"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func reorgTestHash(height, fork int) string {
+	return fmt.Sprintf("%064X", fork<<16|height)
+}
+
+// reorgTestBlock returns block height of fork, following block height-1 of
+// prevFork.
+func reorgTestBlock(height, fork, prevFork int) *BlockInfo {
+	return &BlockInfo{
+		Height:            big.NewInt(int64(height)),
+		Hash:              reorgTestHash(height, fork),
+		PreviousBlockHash: reorgTestHash(height-1, prevFork),
+	}
+}
+
+// newReorgTestMock serves the blocks from to to of fork 1, which branches
+// off fork 0 after block from-1.
+func newReorgTestMock(from, to int) *sdkMock {
+	m := newSdkMock(0)
+
+	for h := from; h <= to; h++ {
+		prevFork := 1
+		if h == from {
+			prevFork = 0
+		}
+
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(blockByHeightRoute, h),
+			RespBody: testBlockJSON(h, reorgTestHash(h, 1), reorgTestHash(h-1, prevFork), 0),
+		})
+	}
+
+	return m
+}
+
+func TestReorgDetector_OnBlock(t *testing.T) {
+	// the node skipped block 3 of fork 0, then switched to fork 1 after
+	// block 4
+	m := newReorgTestMock(5, 6)
+	defer m.Close()
+
+	m.AddRouter(testBlockRouters(3)[0])
+
+	var reorgs []*Reorg
+
+	d, err := NewReorgDetector(m.getTestNetClientUnsafe(), &ReorgOptions{
+		Depth:   5,
+		OnReorg: func(reorg *Reorg) { reorgs = append(reorgs, reorg) },
+	})
+	assert.Nil(t, err)
+
+	for _, h := range []int{1, 2, 4} {
+		reorg, err := d.OnBlock(ctx, reorgTestBlock(h, 0, 0))
+		assert.Nil(t, err)
+		assert.Nil(t, reorg)
+	}
+
+	// the skipped block 3 was requested, and a block seen already is ignored
+	assert.Equal(t, reorgTestHash(3, 0), d.at(big.NewInt(3)).Hash)
+
+	reorg, err := d.OnBlock(ctx, reorgTestBlock(4, 0, 0))
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+
+	for h := 5; h <= 6; h++ {
+		reorg, err := d.OnBlock(ctx, reorgTestBlock(h, 0, 0))
+		assert.Nil(t, err)
+		assert.Nil(t, reorg)
+	}
+
+	reorg, err = d.OnBlock(ctx, reorgTestBlock(7, 1, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, []*Reorg{reorg}, reorgs)
+
+	assert.Equal(t, int64(4), reorg.ForkHeight.Int64())
+	assert.Len(t, reorg.Orphaned, 2)
+	assert.Equal(t, reorgTestHash(5, 0), reorg.Orphaned[0].Hash)
+	assert.Equal(t, reorgTestHash(6, 0), reorg.Orphaned[1].Hash)
+	assert.Len(t, reorg.NewBlocks, 3)
+	assert.Equal(t, reorgTestHash(5, 1), reorg.NewBlocks[0].Hash)
+	assert.Equal(t, reorgTestHash(7, 1), reorg.NewBlocks[2].Hash)
+
+	// the new fork is followed
+	reorg, err = d.OnBlock(ctx, reorgTestBlock(8, 1, 1))
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+}
+
+func TestReorgDetector_OnBlockTooDeep(t *testing.T) {
+	// the node switched to fork 1 after block 3; the blocks down to 4 are
+	// requested
+	m := newReorgTestMock(4, 8)
+	defer m.Close()
+
+	d, err := NewReorgDetector(m.getTestNetClientUnsafe(), &ReorgOptions{Depth: 5})
+	assert.Nil(t, err)
+
+	for h := 1; h <= 8; h++ {
+		_, err := d.OnBlock(ctx, reorgTestBlock(h, 0, 0))
+		assert.Nil(t, err)
+	}
+
+	// the fork is older than the 5 blocks kept
+	_, err = d.OnBlock(ctx, reorgTestBlock(9, 1, 1))
+	assert.Equal(t, ErrReorgTooDeep, err)
+
+	// the detector starts over from the fork
+	reorg, err := d.OnBlock(ctx, reorgTestBlock(10, 1, 1))
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+}
+
+func TestReorgDetector_Watch(t *testing.T) {
+	// block 2 is not served, and the node switched to fork 1 after block 2
+	m := newReorgTestMock(3, 5)
+	defer m.Close()
+
+	var errs []error
+
+	d, err := NewReorgDetector(m.getTestNetClientUnsafe(), &ReorgOptions{
+		Depth:   2,
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	assert.Nil(t, err)
+
+	blocks := make(chan *BlockInfo, 10)
+	for _, b := range []*BlockInfo{
+		// the request of the skipped block 2 fails
+		reorgTestBlock(1, 0, 0),
+		reorgTestBlock(3, 0, 0),
+		// the watch goes on
+		reorgTestBlock(2, 0, 0),
+		reorgTestBlock(3, 0, 0),
+		reorgTestBlock(4, 0, 0),
+		// the fork is older than the 2 blocks kept
+		reorgTestBlock(6, 1, 1),
+		reorgTestBlock(7, 1, 1),
+	} {
+		blocks <- b
+	}
+	close(blocks)
+
+	assert.Nil(t, d.Watch(ctx, blocks))
+
+	assert.Len(t, errs, 2)
+	assert.NotNil(t, errs[0])
+	assert.Equal(t, ErrReorgTooDeep, errs[1])
+	assert.Equal(t, reorgTestHash(7, 1), d.at(big.NewInt(7)).Hash)
+}
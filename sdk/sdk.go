@@ -7,14 +7,17 @@ package sdk
 
 import (
 	"bytes"
-	"errors"
+	"crypto/tls"
 	"github.com/google/go-querystring/query"
 	"github.com/json-iterator/go"
 	"golang.org/x/net/context"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
+	"time"
 )
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
@@ -23,8 +26,41 @@ var json = jsoniter.ConfigCompatibleWithStandardLibrary
 type Config struct {
 	BaseURL *url.URL
 	NetworkType
+	// Websocket configures the websocket connection; nil means defaults.
+	Websocket *WebsocketConfig
+	// Logger receives the events of clients created with this Config;
+	// nil discards them.
+	Logger Logger
+	// Batch limits the requests which post lists of ids; nil means defaults.
+	Batch *BatchConfig
+	// Cache caches mosaic and namespace metadata, see NewMetadataCache;
+	// nil disables caching.
+	Cache *MetadataCache
 }
 
+// WebsocketConfig provides websocket connection configuration
+type WebsocketConfig struct {
+	// URL of the websocket endpoint. When nil it is derived from BaseURL,
+	// see Config.WebsocketURL.
+	URL *url.URL
+	// Origin sent in the handshake, http://localhost by default.
+	Origin string
+	// Header holds extra handshake headers, e.g. Authorization.
+	Header http.Header
+	// TLSConfig is used for wss:// connections.
+	TLSConfig *tls.Config
+	// Proxy returns the HTTP proxy to tunnel through for a given request,
+	// as http.Transport.Proxy does, e.g. http.ProxyFromEnvironment.
+	// ws:// and wss:// are asked for as http:// and https:// respectively.
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+const (
+	defaultWebsocketOrigin = "http://localhost"
+	defaultRestPort        = "3000"
+	websocketPath          = "/ws"
+)
+
 // NewConfig is Config constructor according to 'baseURL' & 'networkType'
 func NewConfig(baseUrl string, networkType NetworkType) (*Config, error) {
 	u, err := url.Parse(baseUrl)
@@ -37,11 +73,55 @@ func NewConfig(baseUrl string, networkType NetworkType) (*Config, error) {
 	return c, nil
 }
 
+// WebsocketURL returns the url of the websocket endpoint of the node.
+// Websocket.URL is used when set. Otherwise the url is derived from BaseURL:
+// http becomes ws and https becomes wss, "/ws" is appended to the path and
+// an http url without a port gets the default REST port 3000.
+// A BaseURL with a ws or wss scheme is returned as is.
+func (c *Config) WebsocketURL() (*url.URL, error) {
+	if c.Websocket != nil && c.Websocket.URL != nil {
+		u := *c.Websocket.URL
+		return &u, nil
+	}
+
+	if c.BaseURL == nil {
+		return nil, ErrInvalidWebsocketURL
+	}
+
+	u := *c.BaseURL
+	switch u.Scheme {
+	case "ws", "wss":
+		return &u, nil
+	case "https":
+		u.Scheme = "wss"
+	case "http", "":
+		u.Scheme = "ws"
+		if u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), defaultRestPort)
+		}
+	default:
+		return nil, ErrInvalidWebsocketURL
+	}
+
+	if u.Host == "" {
+		return nil, ErrInvalidWebsocketURL
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + websocketPath
+	u.RawPath = ""
+	return &u, nil
+}
+
 // Client is Catapult API Client configuration
 type Client struct {
 	client *http.Client // HTTP client used to communicate with the API.
 	config *Config
-	common service // Reuse a single struct instead of allocating one for each service on the heap.
+	log    Logger
+	// divisibilities caches the divisibility of mosaics, which can not change.
+	divisibilities divisibilityCache
+	// publicAccounts caches the resolved public accounts, which can not change.
+	publicAccounts publicAccountCache
+	common         service // Reuse a single struct instead of allocating one for each service on the heap.
 	// Services for communicating to the Catapult REST APIs
 	Blockchain  *BlockchainService
 	Mosaic      *MosaicService
@@ -62,7 +142,10 @@ func NewClient(httpClient *http.Client, conf *Config) *Client {
 		httpClient = http.DefaultClient
 	}
 
-	c := &Client{client: httpClient, config: conf}
+	c := &Client{client: httpClient, config: conf, log: nopLogger{}}
+	if conf != nil {
+		c.log = loggerOrNop(conf.Logger)
+	}
 	c.common.client = c
 	c.Blockchain = (*BlockchainService)(&c.common)
 	c.Mosaic = (*MosaicService)(&c.common)
@@ -74,6 +157,12 @@ func NewClient(httpClient *http.Client, conf *Config) *Client {
 	return c
 }
 
+// SetLogger sets the Logger which receives request events.
+// A nil Logger discards them.
+func (c *Client) SetLogger(l Logger) {
+	c.log = loggerOrNop(l)
+}
+
 // DoNewRequest creates new request, Do it & return result in V
 func (c *Client) DoNewRequest(ctx context.Context, method string, path string, body interface{}, v interface{}) (*http.Response, error) {
 	req, err := c.NewRequest(method, path, body)
@@ -89,11 +178,29 @@ func (c *Client) DoNewRequest(ctx context.Context, method string, path string, b
 	return resp, nil
 }
 
+// doNewRequestOrNotFound is DoNewRequest for resources the node may not know yet,
+// such as a transaction being processed: it returns ErrResourceNotFound on 404.
+func (c *Client) doNewRequestOrNotFound(ctx context.Context, method string, path string, body interface{}, v interface{}) (*http.Response, error) {
+	req, err := c.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(ctx, req, v, true)
+}
+
 // Do sends an API Request and returns a parsed response
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	return c.do(ctx, req, v, false)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}, notFound bool) (*http.Response, error) {
 
 	// set the Context for this request
-	req.WithContext(ctx)
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	c.log.Debug("rest request", "method", req.Method, "url", req.URL.String())
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -104,15 +211,22 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 			return nil, ctx.Err()
 		default:
 		}
+		c.log.Warn("rest request failed", "method", req.Method, "url", req.URL.String(), "err", err)
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	c.log.Debug("rest response", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", time.Since(start))
+
+	if notFound && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrResourceNotFound
+	}
+
 	if resp.StatusCode > 226 || resp.StatusCode < 200 {
 		b := &bytes.Buffer{}
 		b.ReadFrom(resp.Body)
-		return nil, errors.New(b.String())
+		return nil, &responseStatusError{resp.StatusCode, b.String()}
 	}
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
@@ -123,6 +237,7 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 				decErr = nil // ignore EOF errors caused by empty response body
 			}
 			if decErr != nil {
+				c.log.Error("rest response decode failed", "method", req.Method, "url", req.URL.String(), "err", decErr)
 				err = decErr
 			}
 		}
@@ -45,3 +45,10 @@ type AccountTransactionsOption struct {
 	PageSize int    `url:"pageSize,omitempty"`
 	Id       string `url:"id,omitempty"`
 }
+
+// BlockTransactionsOption selects a page of the transactions of a block,
+// the PageSize transactions after the transaction of Id.
+type BlockTransactionsOption struct {
+	PageSize int    `url:"pageSize,omitempty"`
+	Id       string `url:"id,omitempty"`
+}
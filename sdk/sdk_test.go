@@ -6,9 +6,12 @@ package sdk
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
 	"github.com/proximax-storage/proximax-utils-go/mock"
 	"github.com/stretchr/testify/assert"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 )
@@ -114,3 +117,86 @@ func (m *sdkMock) getTestNetClientUnsafe() *Client {
 
 	return client
 }
+
+// testTransferJSON returns a transfer transaction at height, whose id and hash
+// are derived from the height, on the network of the recipient.
+func testTransferJSON(t *testing.T, height int, signer string, recipient *Address, message string, mosaics ...*Mosaic) string {
+	encoded, err := recipient.Encoded()
+	assert.Nil(t, err)
+
+	ms := make([]string, len(mosaics))
+	for i, m := range mosaics {
+		id, amount := mosaicIdToBigInt(m.MosaicId).Uint64(), m.Amount.Uint64()
+		ms[i] = fmt.Sprintf(`{"id": [%d, %d], "amount": [%d, %d]}`, id&0xFFFFFFFF, id>>32, amount&0xFFFFFFFF, amount>>32)
+	}
+
+	return fmt.Sprintf(`{
+	"meta": {"height": [%d, 0], "hash": "%064X", "merkleComponentHash": "%064X", "index": 0, "id": "%024X"},
+	"transaction": {
+		"signature": "%0128X",
+		"signer": "%s",
+		"version": %d,
+		"type": 16724,
+		"fee": [0, 0],
+		"deadline": [1, 0],
+		"recipient": "%s",
+		"message": {"type": 0, "payload": "%s"},
+		"mosaics": [%s]
+	}
+}`, height, height, height, height, 0, signer, uint64(recipient.Type)<<8|3, encoded, hex.EncodeToString([]byte(message)), strings.Join(ms, ","))
+}
+
+// testBlockJSON returns block height, whose timestamp is its height in
+// seconds.
+func testBlockJSON(height int, hash, previousHash string, numTransactions int) string {
+	return fmt.Sprintf(`{
+	"meta": {"hash": "%s", "generationHash": "%064X", "totalFee": [0, 0], "numTransactions": %d},
+	"block": {
+		"signature": "%0128X",
+		"signer": "321DE652C4D3362FC2DDF7800F6582F4A10CFEA134B81F8AB6E4BE78BBA4D18E",
+		"version": 36867,
+		"type": 32835,
+		"height": [%d, 0],
+		"timestamp": [%d, 0],
+		"difficulty": [276447232, 23283],
+		"previousBlockHash": "%s",
+		"blockTransactionsHash": "%064X"
+	}
+}`, hash, 0, numTransactions, 0, height, height*1000, previousHash, 0)
+}
+
+// testBlockRouters returns the routes of the blocks of heights, whose hashes
+// are derived from the height.
+func testBlockRouters(heights ...int) []*mock.Router {
+	routers := make([]*mock.Router, len(heights))
+	for i, h := range heights {
+		routers[i] = &mock.Router{
+			Path:     fmt.Sprintf(blockByHeightRoute, h),
+			RespBody: testBlockJSON(h, fmt.Sprintf("%064X", h), fmt.Sprintf("%064X", h-1), 0),
+		}
+	}
+	return routers
+}
+
+// testNamespaceJSON returns the info of namespace id, a root namespace unless
+// parent is not zero.
+func testNamespaceJSON(id, parent uint64, endHeight string) string {
+	nsType, depth, levels := 0, 1, fmt.Sprintf(`"level0": [%d, 0]`, id)
+	if parent != 0 {
+		nsType, depth, levels = 1, 2, fmt.Sprintf(`"level0": [%d, 0], "level1": [%d, 0]`, parent, id)
+	}
+
+	return fmt.Sprintf(`{
+	"meta": {"active": true, "index": 0, "id": "%024X"},
+	"namespace": {
+		"namespaceId": [%d, 0],
+		"type": %d,
+		"depth": %d,
+		%s,
+		"parentId": [%d, 0],
+		"owner": "321DE652C4D3362FC2DDF7800F6582F4A10CFEA134B81F8AB6E4BE78BBA4D18E",
+		"startHeight": [1, 0],
+		"endHeight": %s
+	}
+}`, id, id, nsType, depth, levels, parent, endHeight)
+}
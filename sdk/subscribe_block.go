@@ -77,10 +77,13 @@ func (s *subscribe) closeChannel() error {
 // Unsubscribe terminates the specified subscription.
 // It does not have any specific param.
 func (c *subscribe) unsubscribe() error {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	c.conn = connectsWs[c.getAdd()].conn
 	if err := websocket.JSON.Send(c.conn, sendJson{
-		Uid:       c.Uid,
-		Subscribe: c.Subscribe,
+		Uid:         c.Uid,
+		Unsubscribe: c.Subscribe,
 	}); err != nil {
 		return err
 	}
@@ -88,6 +91,7 @@ func (c *subscribe) unsubscribe() error {
 	if err := c.closeChannel(); err != nil {
 		return err
 	}
+	loggerOrNop(c.log).Info("websocket unsubscribed", "subscribe", c.Subscribe, "uid", c.Uid)
 
 	return nil
 }
@@ -105,6 +109,8 @@ func (c *SubscribeService) newSubscribe(route string) (*subscribe, error) {
 	return subMsg, nil
 }
 
+// getClient returns the client whose connection serves add. It is called
+// with wsMu held.
 func (c *SubscribeService) getClient(add string) (*ClientWebsocket, error) {
 	if len(connectsWs) == 0 {
 		obj := uidConn{
@@ -118,11 +124,12 @@ func (c *SubscribeService) getClient(add string) (*ClientWebsocket, error) {
 		c.client.Uid = obj.uid
 		return c.client, nil
 	} else {
-		client, err := NewConnectWs(c.client.config.BaseURL.String(), *c.client.duration)
+		client, err := NewConnectWsWithConfig(c.client.config, *c.client.duration)
 
 		if err != nil {
 			return nil, err
 		}
+		client.log = c.client.log
 		obj := uidConn{
 			uid:  client.Uid,
 			conn: client.client,
@@ -135,6 +142,9 @@ func (c *SubscribeService) getClient(add string) (*ClientWebsocket, error) {
 // Block notifies for every new block.
 // The message contains the BlockInfo struct.
 func (c *SubscribeService) Block() (*SubscribeBlock, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient("block"); err != nil {
 		return nil, err
 	} else {
@@ -156,6 +166,9 @@ func (c *SubscribeService) Block() (*SubscribeBlock, error) {
 // address is included in a block.
 // The message contains the transaction.
 func (c *SubscribeService) ConfirmedAdded(add *Address) (*SubscribeTransaction, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -178,6 +191,9 @@ func (c *SubscribeService) ConfirmedAdded(add *Address) (*SubscribeTransaction,
 // address is in unconfirmed state and waiting to be included in a block.
 // The message contains the transaction.
 func (c *SubscribeService) UnconfirmedAdded(add *Address) (*SubscribeTransaction, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -200,6 +216,9 @@ func (c *SubscribeService) UnconfirmedAdded(add *Address) (*SubscribeTransaction
 // address was in unconfirmed state but not anymore.
 // The message contains the transaction hash.
 func (c *SubscribeService) UnconfirmedRemoved(add *Address) (*SubscribeHash, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -220,6 +239,9 @@ func (c *SubscribeService) UnconfirmedRemoved(add *Address) (*SubscribeHash, err
 // Status notifies when a transaction related to an address rises an error.
 // The message contains the error message and the transaction hash.
 func (c *SubscribeService) Status(add *Address) (*SubscribeStatus, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -241,6 +263,9 @@ func (c *SubscribeService) Status(add *Address) (*SubscribeStatus, error) {
 // address is in partial state and waiting to have all required cosigners.
 // The message contains a transaction.
 func (c *SubscribeService) PartialAdded(add *Address) (*SubscribeTransaction, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -262,6 +287,9 @@ func (c *SubscribeService) PartialAdded(add *Address) (*SubscribeTransaction, er
 // address was in partial state but not anymore.
 // The message contains the transaction hash.
 func (c *SubscribeService) PartialRemoved(add *Address) (*SubscribePartialRemoved, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -283,6 +311,9 @@ func (c *SubscribeService) PartialRemoved(add *Address) (*SubscribePartialRemove
 // address is added to an aggregate bonded transaction with partial state.
 // The message contains the cosignature signed transaction.
 func (c *SubscribeService) Cosignature(add *Address) (*SubscribeSigner, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	if client, err := c.getClient(add.Address); err != nil {
 		return nil, err
 	} else {
@@ -301,6 +332,9 @@ func (c *SubscribeService) Cosignature(add *Address) (*SubscribeSigner, error) {
 }
 
 func (c *SubscribeService) Error(add *Address) (*SubscribeError, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
 	address := "block"
 	if add != nil {
 		address = add.Address
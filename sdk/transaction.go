@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"github.com/proximax-storage/proximax-utils-go/net"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type TransactionService service
@@ -32,8 +34,64 @@ func (txs *TransactionService) GetTransaction(ctx context.Context, id string) (T
 	return MapTransaction(&b)
 }
 
-// Returns transaction information for a given set of transaction id or hash
+// Returns transaction information for a given set of transaction id or hash,
+// in the order of ids, followed by the transactions returned for none of ids.
+// Long lists are requested in chunks, see BatchConfig; if only some ids fail,
+// the other transactions are returned along with a *BatchError.
 func (txs *TransactionService) GetTransactions(ctx context.Context, ids []string) ([]Transaction, error) {
+	var (
+		found     = make([]Transaction, len(ids))
+		unmatched = make([][]Transaction, len(ids))
+	)
+
+	err := txs.client.doBatch(ctx, ids, func(ctx context.Context, offset int, ids []string) error {
+		trs, err := txs.getTransactions(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		// ids are either hashes or ids of transactions
+		keys := make([][]string, len(trs))
+		for j, tr := range trs {
+			if info := tr.GetAbstractTransaction().TransactionInfo; info != nil {
+				keys[j] = []string{strings.ToUpper(info.Id), strings.ToUpper(info.Hash.String())}
+			}
+		}
+
+		upper := make([]string, len(ids))
+		for i, id := range ids {
+			upper[i] = strings.ToUpper(id)
+		}
+
+		byId, others := batchMatch(upper, keys)
+		for i, j := range byId {
+			if j >= 0 {
+				found[offset+i] = trs[j]
+			}
+		}
+		for _, j := range others {
+			unmatched[offset] = append(unmatched[offset], trs[j])
+		}
+		return nil
+	})
+	if batchFailed(err) {
+		return nil, err
+	}
+
+	trs := make([]Transaction, 0, len(found))
+	for _, tr := range found {
+		if tr != nil {
+			trs = append(trs, tr)
+		}
+	}
+	for _, chunk := range unmatched {
+		trs = append(trs, chunk...)
+	}
+
+	return trs, err
+}
+
+func (txs *TransactionService) getTransactions(ctx context.Context, ids []string) ([]Transaction, error) {
 	var b bytes.Buffer
 	txIds := &TransactionIdsDTO{
 		ids,
@@ -117,3 +175,252 @@ func (txs *TransactionService) announceTransaction(ctx context.Context, tx inter
 
 	return m.Message, nil
 }
+
+// transaction status groups returned by the REST API
+const (
+	statusGroupUnconfirmed = "unconfirmed"
+	statusGroupConfirmed   = "confirmed"
+	statusGroupFailed      = "failed"
+)
+
+const defaultPollInterval = time.Second
+
+// AnnounceAndWait announces a transaction and waits until it is confirmed, rejected or expired.
+// When opts.Websocket is set it subscribes to unconfirmedAdded, confirmedAdded and status of the signer
+// before announcing, otherwise it polls the transaction status.
+// The subscriptions are registered for the signer address, so they must not be shared with other subscribers.
+func (txs *TransactionService) AnnounceAndWait(ctx context.Context, tx *SignedTransaction, opts *AnnounceOptions) (*TransactionResult, error) {
+	if tx == nil {
+		return nil, ErrNilSignedTransaction
+	}
+
+	if opts == nil {
+		opts = &AnnounceOptions{}
+	}
+
+	deadline, err := deadlineFromPayload(tx.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Websocket != nil {
+		signer, err := signerFromPayload(tx.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := NewAddressFromPublicKey(signer, txs.client.config.NetworkType)
+		if err != nil {
+			return nil, err
+		}
+
+		if subs, err := subscribeLifecycle(opts.Websocket, address); err == nil {
+			defer subs.unsubscribe()
+
+			if _, err = txs.Announce(ctx, tx); err != nil {
+				return nil, err
+			}
+
+			return txs.waitSubscriptions(ctx, subs, tx.Hash, deadline, opts)
+		}
+	}
+
+	if _, err = txs.Announce(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return txs.waitPolling(ctx, tx.Hash, deadline, opts)
+}
+
+type lifecycleSubscriptions struct {
+	unconfirmed *SubscribeTransaction
+	confirmed   *SubscribeTransaction
+	status      *SubscribeStatus
+}
+
+func subscribeLifecycle(ws *ClientWebsocket, address *Address) (*lifecycleSubscriptions, error) {
+	var (
+		subs = &lifecycleSubscriptions{}
+		err  error
+	)
+
+	if subs.unconfirmed, err = ws.Subscribe.UnconfirmedAdded(address); err != nil {
+		return nil, err
+	}
+
+	if subs.confirmed, err = ws.Subscribe.ConfirmedAdded(address); err != nil {
+		subs.unsubscribe()
+		return nil, err
+	}
+
+	if subs.status, err = ws.Subscribe.Status(address); err != nil {
+		subs.unsubscribe()
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+func (s *lifecycleSubscriptions) unsubscribe() {
+	if s.unconfirmed != nil {
+		s.unconfirmed.Unsubscribe()
+	}
+
+	if s.confirmed != nil {
+		s.confirmed.Unsubscribe()
+	}
+
+	if s.status != nil {
+		s.status.Unsubscribe()
+	}
+}
+
+func (txs *TransactionService) waitSubscriptions(ctx context.Context, subs *lifecycleSubscriptions, hash Hash, deadline *Deadline,
+	opts *AnnounceOptions) (*TransactionResult, error) {
+	expired := time.NewTimer(time.Until(deadline.Time))
+	defer expired.Stop()
+
+	notified := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case tx := <-subs.unconfirmed.Ch:
+			if !notified && hasHash(tx, hash) && opts.OnUnconfirmed != nil {
+				notified = true
+				opts.OnUnconfirmed(hash)
+			}
+
+		case tx := <-subs.confirmed.Ch:
+			if hasHash(tx, hash) {
+				return &TransactionResult{
+					State:       TransactionConfirmed,
+					Hash:        hash,
+					Height:      tx.GetAbstractTransaction().Height,
+					Transaction: tx,
+				}, nil
+			}
+
+		case status := <-subs.status.Ch:
+			if status != nil && strings.EqualFold(status.Hash.String(), hash.String()) {
+				return &TransactionResult{
+					State:  TransactionRejected,
+					Hash:   hash,
+					Status: status.Status,
+				}, nil
+			}
+
+		case <-expired.C:
+			return txs.expire(ctx, hash)
+		}
+	}
+}
+
+func (txs *TransactionService) waitPolling(ctx context.Context, hash Hash, deadline *Deadline, opts *AnnounceOptions) (*TransactionResult, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	notified := false
+
+	for {
+		if time.Now().After(deadline.Time) {
+			return txs.expire(ctx, hash)
+		}
+
+		res, group, err := txs.checkStatus(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if res != nil {
+			return res, nil
+		}
+
+		if !notified && group == statusGroupUnconfirmed && opts.OnUnconfirmed != nil {
+			notified = true
+			opts.OnUnconfirmed(hash)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// expire makes the last status check after the deadline of a transaction has passed
+func (txs *TransactionService) expire(ctx context.Context, hash Hash) (*TransactionResult, error) {
+	res, _, err := txs.checkStatus(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		return res, nil
+	}
+
+	return &TransactionResult{State: TransactionExpired, Hash: hash}, nil
+}
+
+// checkStatus returns the final result of a transaction or nil when it is still pending.
+// A status not found is treated as pending, because the node does not know the transaction until it is processed.
+func (txs *TransactionService) checkStatus(ctx context.Context, hash Hash) (*TransactionResult, string, error) {
+	dto := &transactionStatusDTO{}
+
+	_, err := txs.client.doNewRequestOrNotFound(ctx, http.MethodGet, fmt.Sprintf(transactionStatusRoute, hash), nil, dto)
+	if err == ErrResourceNotFound {
+		txs.client.log.Debug("transaction status not available, retrying", "hash", hash)
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	status, err := dto.toStruct()
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch status.Group {
+	case statusGroupConfirmed:
+		tx, err := txs.GetTransaction(ctx, hash.String())
+		if err != nil {
+			return nil, "", err
+		}
+
+		return &TransactionResult{
+			State:       TransactionConfirmed,
+			Hash:        hash,
+			Status:      status.Status,
+			Height:      status.Height,
+			Transaction: tx,
+		}, status.Group, nil
+
+	case statusGroupFailed:
+		return &TransactionResult{
+			State:  TransactionRejected,
+			Hash:   hash,
+			Status: status.Status,
+		}, status.Group, nil
+	}
+
+	return nil, status.Group, nil
+}
+
+func hasHash(tx Transaction, hash Hash) bool {
+	if tx == nil {
+		return false
+	}
+
+	atx := tx.GetAbstractTransaction()
+
+	return atx.TransactionInfo != nil && strings.EqualFold(atx.TransactionInfo.Hash.String(), hash.String())
+}
@@ -1,5 +1,12 @@
 package sdk
 
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
 type transactionStatusDTOs []*transactionStatusDTO
 
 func (t *transactionStatusDTOs) toStruct() ([]*TransactionStatus, error) {
@@ -17,3 +24,40 @@ func (t *transactionStatusDTOs) toStruct() ([]*TransactionStatus, error) {
 
 	return statuses, nil
 }
+
+// byte offsets of the fields inside a signed transaction payload
+const (
+	payloadSignerOffset   = 4 + 64
+	payloadDeadlineOffset = payloadSignerOffset + 32 + 2 + 2 + 8
+	payloadHeaderSize     = payloadDeadlineOffset + 8
+)
+
+// signerFromPayload returns the public key of the account which signed the payload
+func signerFromPayload(payload string) (string, error) {
+	b, err := hex.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) < payloadHeaderSize {
+		return "", ErrInvalidSignedPayload
+	}
+
+	return strings.ToUpper(hex.EncodeToString(b[payloadSignerOffset : payloadSignerOffset+32])), nil
+}
+
+// deadlineFromPayload returns the deadline encoded into the payload
+func deadlineFromPayload(payload string) (*Deadline, error) {
+	b, err := hex.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < payloadHeaderSize {
+		return nil, ErrInvalidSignedPayload
+	}
+
+	ms := binary.LittleEndian.Uint64(b[payloadDeadlineOffset:payloadHeaderSize])
+
+	return &Deadline{TimestampNemesisBlock.Add(time.Duration(ms) * time.Millisecond)}, nil
+}
@@ -1354,6 +1354,103 @@ func (dto *secretProofTransactionDTO) toStruct() (*SecretProofTransaction, error
 	}, nil
 }
 
+// AccountLinkTransaction links the signer to a remote account, which then
+// harvests with the importance of the signer without exposing its key
+type AccountLinkTransaction struct {
+	AbstractTransaction
+	RemoteAccount *PublicAccount
+	LinkAction    AccountLinkAction
+}
+
+func NewAccountLinkTransaction(deadline *Deadline, remoteAccount *PublicAccount, linkAction AccountLinkAction, networkType NetworkType) (*AccountLinkTransaction, error) {
+	if remoteAccount == nil {
+		return nil, ErrNilRemoteAccount
+	}
+	if linkAction != LinkAccount && linkAction != UnlinkAccount {
+		return nil, ErrInvalidLinkAction
+	}
+
+	return &AccountLinkTransaction{
+		AbstractTransaction: AbstractTransaction{
+			Version:     2,
+			Deadline:    deadline,
+			Type:        AccountLink,
+			NetworkType: networkType,
+		},
+		RemoteAccount: remoteAccount,
+		LinkAction:    linkAction,
+	}, nil
+}
+
+func (tx *AccountLinkTransaction) GetAbstractTransaction() *AbstractTransaction {
+	return &tx.AbstractTransaction
+}
+
+func (tx *AccountLinkTransaction) String() string {
+	return fmt.Sprintf(
+		`
+			"AbstractTransaction": %s,
+			"RemoteAccount": %s,
+			"LinkAction": %s
+		`,
+		tx.AbstractTransaction.String(),
+		tx.RemoteAccount,
+		tx.LinkAction,
+	)
+}
+
+func (tx *AccountLinkTransaction) generateBytes() ([]byte, error) {
+	builder := flatbuffers.NewBuilder(0)
+
+	k, err := hex.DecodeString(tx.RemoteAccount.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	kV := transactions.TransactionBufferCreateByteVector(builder, k)
+
+	v, signatureV, signerV, deadlineV, fV, err := tx.AbstractTransaction.generateVectors(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions.AccountLinkTransactionBufferStart(builder)
+	transactions.TransactionBufferAddSize(builder, 120+len(k)+1)
+	tx.AbstractTransaction.buildVectors(builder, v, signatureV, signerV, deadlineV, fV)
+	transactions.AccountLinkTransactionBufferAddRemoteAccountKey(builder, kV)
+	transactions.AccountLinkTransactionBufferAddLinkAction(builder, byte(tx.LinkAction))
+	t := transactions.TransactionBufferEnd(builder)
+	builder.Finish(t)
+
+	return accountLinkTransactionSchema().serialize(builder.FinishedBytes()), nil
+}
+
+type accountLinkTransactionDTO struct {
+	Tx struct {
+		abstractTransactionDTO
+		RemoteAccountKey string            `json:"remoteAccountKey"`
+		LinkAction       AccountLinkAction `json:"linkAction"`
+	} `json:"transaction"`
+	TDto transactionInfoDTO `json:"meta"`
+}
+
+func (dto *accountLinkTransactionDTO) toStruct() (*AccountLinkTransaction, error) {
+	atx, err := dto.Tx.abstractTransactionDTO.toStruct(dto.TDto.toStruct())
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := NewAccountFromPublicKey(dto.Tx.RemoteAccountKey, atx.NetworkType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountLinkTransaction{
+		*atx,
+		remote,
+		dto.Tx.LinkAction,
+	}, nil
+}
+
 type CosignatureTransaction struct {
 	TransactionToCosign *AggregateTransaction
 }
@@ -1516,6 +1613,57 @@ func (dto *transactionStatusDTO) toStruct() (*TransactionStatus, error) {
 	}, nil
 }
 
+// TransactionState describes the final state of an announced transaction
+type TransactionState uint8
+
+// TransactionState enums
+const (
+	TransactionConfirmed TransactionState = iota
+	TransactionRejected
+	TransactionExpired
+)
+
+func (s TransactionState) String() string {
+	switch s {
+	case TransactionConfirmed:
+		return "confirmed"
+	case TransactionRejected:
+		return "rejected"
+	case TransactionExpired:
+		return "expired"
+	}
+	return fmt.Sprintf("%d", s)
+}
+
+// AnnounceOptions configures TransactionService.AnnounceAndWait
+type AnnounceOptions struct {
+	// Websocket is used to follow the transaction. Polling is used when it is nil or subscribing fails
+	Websocket *ClientWebsocket
+	// PollInterval between two transaction status requests, one second by default
+	PollInterval time.Duration
+	// OnUnconfirmed is called once when the transaction reaches the unconfirmed state
+	OnUnconfirmed func(hash Hash)
+}
+
+// TransactionResult is the final state of an announced transaction
+type TransactionResult struct {
+	State       TransactionState
+	Hash        Hash
+	Status      string
+	Height      *big.Int
+	Transaction Transaction
+}
+
+func (r *TransactionResult) String() string {
+	return str.StructToString(
+		"TransactionResult",
+		str.NewField("State", str.StringPattern, r.State),
+		str.NewField("Hash", str.StringPattern, r.Hash),
+		str.NewField("Status", str.StringPattern, r.Status),
+		str.NewField("Height", str.StringPattern, r.Height),
+	)
+}
+
 // TransactionIds
 type TransactionIdsDTO struct {
 	Ids []string `json:"transactionIds"`
@@ -1593,6 +1741,7 @@ var transactionTypes = []transactionTypeStruct{
 	{Lock, 16716, 0x414C},
 	{SecretLock, 16972, 0x424C},
 	{SecretProof, 17228, 0x434C},
+	{AccountLink, 16715, 0x414B},
 }
 
 type TransactionType uint16
@@ -1609,6 +1758,7 @@ const (
 	Lock
 	SecretLock
 	SecretProof
+	AccountLink
 )
 
 func (t TransactionType) Hex() uint16 {
@@ -1637,6 +1787,18 @@ const (
 	Remove
 )
 
+type AccountLinkAction uint8
+
+func (a AccountLinkAction) String() string {
+	return fmt.Sprintf("%d", a)
+}
+
+// AccountLinkAction enums
+const (
+	LinkAccount AccountLinkAction = iota
+	UnlinkAccount
+)
+
 type Hash string
 
 func (h Hash) String() string {
@@ -1832,6 +1994,20 @@ func MapTransaction(b *bytes.Buffer) (Transaction, error) {
 			return nil, err
 		}
 
+		return tx, nil
+	case AccountLink:
+		dto := accountLinkTransactionDTO{}
+
+		err := json.Unmarshal(b.Bytes(), &dto)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := dto.toStruct()
+		if err != nil {
+			return nil, err
+		}
+
 		return tx, nil
 	}
 
@@ -185,3 +185,19 @@ func secretProofTransactionSchema() *schema {
 		},
 	}
 }
+
+func accountLinkTransactionSchema() *schema {
+	return &schema{
+		[]schemaAttribute{
+			newScalarAttribute("size", IntSize),
+			newArrayAttribute("signature", ByteSize),
+			newArrayAttribute("signer", ByteSize),
+			newScalarAttribute("version", ShortSize),
+			newScalarAttribute("type", ShortSize),
+			newArrayAttribute("fee", IntSize),
+			newArrayAttribute("deadline", IntSize),
+			newArrayAttribute("remoteAccountKey", ByteSize),
+			newScalarAttribute("linkAction", ByteSize),
+		},
+	}
+}
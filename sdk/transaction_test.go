@@ -8,10 +8,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/proximax-storage/nem2-sdk-go/sdk/wstest"
 	"github.com/proximax-storage/proximax-utils-go/mock"
 	"github.com/proximax-storage/proximax-utils-go/tests"
 	"github.com/stretchr/testify/assert"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 )
@@ -180,6 +182,12 @@ var (
 	secretLockTransactionSigningCorr = "EA0000005A3B75AE172855381353250EA9A1DFEB86E9280C0006B8FD997C2FCECF211C9A260E76CB704A22EAD4648F18E6931381921A4EDC7D309C32275D0147E9BAD3051026D70E1954775749C6811084D6450A3184D977383F0E4282CD47118AF3775503904C420000000000000000010000000000000029CF5FD941AD25D58096980000000000640000000000000000B778A39A3663719DFC5E48C9D78431B1E45C2AF9DF538782BF199C189DABEAC7680ADA57DCEC8EEE91C4E3BF3BFA9AF6FFDE90CD1D249D1C6121D7B759A001B190E8FEBD671DD41BEE94EC3BA5831CB608A312C2F203BA84AC"
 
 	lockFundsTransactionSigningCorr = "B0000000D079047B87DCEDA0DE68558C1322A453D55D52BDA2778D66C5344BF79EE9E946C731F9ED565E5A854AFC0A1E1476B571940F920F33ADD9BAC245DB46A59794051026D70E1954775749C6811084D6450A3184D977383F0E4282CD47118AF3775503904C410000000000000000010000000000000029CF5FD941AD25D5809698000000000064000000000000008498B38D89C1DC8A448EA5824938FF828926CD9F7747B1844B59B4B6807E878B"
+
+	// the remote account and payload end of the AccountLinkTransaction test
+	// of nem2-sdk-typescript, with the type 0x414B of this SDK, as 0x414C is
+	// still the lock funds type here
+	accountLinkTransactionRemoteKey   = "C2F93346E27CE6AD1A9F8F5E3066F8326593A406BDF357ACB041E2F9AB402EFE"
+	accountLinkTransactionSigningCorr = "990000002FB94C89C2062BDD876992D6D3CE39D7CF0ABAF9BF952A5636D374D4D1EB30AD78CF4AF9AE58695F2C680769EEC349F637E1FA87A0BF6FF2B5DE7C62496BFD001026D70E1954775749C6811084D6450A3184D977383F0E4282CD47118AF3775502904B4100000000000000000100000000000000C2F93346E27CE6AD1A9F8F5E3066F8326593A406BDF357ACB041E2F9AB402EFE00"
 )
 
 func TestTransactionService_GetTransaction_TransferTransaction(t *testing.T) {
@@ -660,6 +668,48 @@ func TestSecretProofTransactionSigning(t *testing.T) {
 	assert.Equal(t, secretProofTransactionSigningCorr, b.Payload)
 }
 
+func TestAccountLinkTransactionSigning(t *testing.T) {
+	acc, err := NewAccountFromPrivateKey("787225aaff3d2c71f4ffa32d4f19ec4922f3cd869747f267378f81f8e3fcb12d", MijinTest)
+
+	assert.Nilf(t, err, "NewAccountFromPrivateKey returned error: %s", err)
+
+	p, err := NewAccountFromPublicKey(accountLinkTransactionRemoteKey, MijinTest)
+
+	assert.Nilf(t, err, "NewAccountFromPublicKey returned error: %s", err)
+
+	tx, err := NewAccountLinkTransaction(fakeDeadline, p, LinkAccount, MijinTest)
+
+	assert.Nilf(t, err, "NewAccountLinkTransaction returned error: %s", err)
+
+	b, err := signTransactionWith(tx, acc)
+
+	assert.Nilf(t, err, "signTransactionWith returned error: %s", err)
+	assert.Equal(t, accountLinkTransactionSigningCorr, b.Payload)
+	assert.Equal(t, accountLinkTransactionRemoteKey+"00", b.Payload[240:])
+	assert.Equal(t, "9E4802895ECEF8571E0FEFB4E4C57E943C043C86B4D58992E7920C26BE67D4B0", b.Hash.String())
+
+	_, err = NewAccountLinkTransaction(fakeDeadline, nil, LinkAccount, MijinTest)
+	assert.Equal(t, ErrNilRemoteAccount, err)
+
+	_, err = NewAccountLinkTransaction(fakeDeadline, p, UnlinkAccount+1, MijinTest)
+	assert.Equal(t, ErrInvalidLinkAction, err)
+}
+
+func TestMapTransaction_AccountLinkTransaction(t *testing.T) {
+	txr := `{"meta":{"height":[7,0],"hash":"45AC1259DABD7163B2816232773E66FC00342BB8DD5C965D4B784CD575FDFAF1","merkleComponentHash":"45AC1259DABD7163B2816232773E66FC00342BB8DD5C965D4B784CD575FDFAF1","index":0,"id":"5B686E97F0C0EA00017B9437"},"transaction":{"signer":"9C2086FE49B7A00578009B705AD719DB7E02A27870C67966AAA40540C136E248","version":36866,"type":16715,"fee":[0,0],"deadline":[1,0],"remoteAccountKey":"9A49366406ACA952B88BADF5F1E9BE6CE4968141035A60BE503273EA65456B24","linkAction":0}}`
+
+	tx, err := MapTransaction(bytes.NewBuffer([]byte(txr)))
+
+	assert.Nilf(t, err, "MapTransaction returned error: %s", err)
+
+	link, ok := tx.(*AccountLinkTransaction)
+	assert.True(t, ok)
+	assert.Equal(t, AccountLink, link.Type)
+	assert.Equal(t, LinkAccount, link.LinkAction)
+	assert.Equal(t, "9A49366406ACA952B88BADF5F1E9BE6CE4968141035A60BE503273EA65456B24", link.RemoteAccount.PublicKey)
+	assert.Equal(t, MijinTest, link.RemoteAccount.Address.Type)
+}
+
 func TestDeadline(t *testing.T) {
 	if !time.Now().Before(NewDeadline(time.Hour * 2).Time) {
 		t.Error("now is before deadline localtime")
@@ -690,3 +740,185 @@ func TestMapTransactions(t *testing.T) {
 	assert.Nilf(t, err, "MapTransaction returned error: %s", err)
 	assert.True(t, len(txs) == 2)
 }
+
+func TestTransactionService_AnnounceAndWait(t *testing.T) {
+	a, err := NewAccountFromPrivateKey("787225aaff3d2c71f4ffa32d4f19ec4922f3cd869747f267378f81f8e3fcb12d", MijinTest)
+	assert.Nilf(t, err, "NewAccountFromPrivateKey returned error: %s", err)
+
+	signTransfer := func(deadline *Deadline) *SignedTransaction {
+		tx, err := NewTransferTransaction(
+			deadline,
+			NewAddress("SDUP5PLHDXKBX3UU5Q52LAY4WYEKGEWC6IB3VBFM", MijinTest),
+			[]*Mosaic{Xem(100)},
+			NewPlainMessage(""),
+			MijinTest,
+		)
+		assert.Nilf(t, err, "NewTransferTransaction returned error: %s", err)
+
+		stx, err := a.Sign(tx)
+		assert.Nilf(t, err, "Account.Sign returned error: %s", err)
+
+		return stx
+	}
+
+	newServer := func(stx *SignedTransaction, group, status string) *sdkMock {
+		m := newSdkMock(0)
+		m.AddRouter(&mock.Router{
+			Path:     transactionsRoute,
+			RespBody: `{"message": "packet 9 was pushed to the network via /transaction"}`,
+		})
+		if group == "" {
+			m.AddRouter(&mock.Router{
+				Path:         fmt.Sprintf(transactionStatusRoute, stx.Hash),
+				RespHttpCode: 500,
+			})
+		} else {
+			m.AddRouter(&mock.Router{
+				Path:     fmt.Sprintf(transactionStatusRoute, stx.Hash),
+				RespBody: fmt.Sprintf(`{"group": "%s", "status": "%s", "hash": "%s", "deadline": [1, 0], "height": [42, 0]}`, group, status, stx.Hash),
+			})
+		}
+		m.AddRouter(&mock.Router{
+			Path:     fmt.Sprintf(transactionRoute, stx.Hash),
+			RespBody: transactionJson,
+		})
+		return m
+	}
+
+	opts := &AnnounceOptions{PollInterval: time.Millisecond}
+
+	t.Run("confirmed", func(t *testing.T) {
+		stx := signTransfer(NewDeadline(time.Hour))
+		m := newServer(stx, "confirmed", "Success")
+		defer m.Close()
+
+		res, err := m.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, stx, opts)
+
+		assert.Nilf(t, err, "TransactionService.AnnounceAndWait returned error: %s", err)
+		assert.Equal(t, TransactionConfirmed, res.State)
+		assert.Equal(t, big.NewInt(42), res.Height)
+		tests.ValidateStringers(t, transaction, res.Transaction)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		stx := signTransfer(NewDeadline(time.Hour))
+		m := newServer(stx, "failed", "Failure_Core_Insufficient_Balance")
+		defer m.Close()
+
+		res, err := m.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, stx, opts)
+
+		assert.Nilf(t, err, "TransactionService.AnnounceAndWait returned error: %s", err)
+		assert.Equal(t, TransactionRejected, res.State)
+		assert.Equal(t, "Failure_Core_Insufficient_Balance", res.Status)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		stx := signTransfer(NewDeadline(50 * time.Millisecond))
+		m := newServer(stx, "unconfirmed", "Success")
+		defer m.Close()
+
+		var unconfirmed Hash
+		res, err := m.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, stx, &AnnounceOptions{
+			PollInterval:  time.Millisecond,
+			OnUnconfirmed: func(hash Hash) { unconfirmed = hash },
+		})
+
+		assert.Nilf(t, err, "TransactionService.AnnounceAndWait returned error: %s", err)
+		assert.Equal(t, TransactionExpired, res.State)
+		assert.Equal(t, stx.Hash, unconfirmed)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		stx := signTransfer(NewDeadline(time.Hour))
+		// the node fails to answer the status request
+		m := newServer(stx, "", "")
+		defer m.Close()
+
+		_, err := m.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, stx, opts)
+
+		assert.NotNil(t, err)
+	})
+
+	waitWebsocket := func(t *testing.T, publish func(srv *wstest.Server, stx *SignedTransaction, address string) error) *TransactionResult {
+		stx := signTransfer(NewDeadline(time.Hour))
+		m := newServer(stx, "unconfirmed", "Success")
+		defer m.Close()
+
+		srv, ws := newWsTestClient(t)
+		defer srv.Close()
+
+		address, err := NewAddressFromPublicKey(a.PublicAccount.PublicKey, TestNet)
+		assert.Nilf(t, err, "NewAddressFromPublicKey returned error: %s", err)
+
+		type result struct {
+			res *TransactionResult
+			err error
+		}
+		done := make(chan result, 1)
+
+		go func() {
+			res, err := m.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, stx, &AnnounceOptions{Websocket: ws})
+			done <- result{res, err}
+		}()
+
+		assert.Nil(t, srv.WaitSubscribed(pathStatus+"/"+address.Address, wsWait))
+		assert.Nil(t, publish(srv, stx, address.Address))
+
+		select {
+		case r := <-done:
+			assert.Nilf(t, r.err, "TransactionService.AnnounceAndWait returned error: %s", r.err)
+			return r.res
+		case <-time.After(wsWait):
+			t.Fatal("AnnounceAndWait did not return")
+			return nil
+		}
+	}
+
+	t.Run("websocket confirmed", func(t *testing.T) {
+		res := waitWebsocket(t, func(srv *wstest.Server, stx *SignedTransaction, address string) error {
+			_, err := srv.PublishTransaction(pathConfirmedAdded, address, []byte(strings.Replace(transactionJson, "45AC1259DABD7163B2816232773E66FC00342BB8DD5C965D4B784CD575FDFAF1", stx.Hash.String(), -1)))
+			return err
+		})
+
+		assert.Equal(t, TransactionConfirmed, res.State)
+		assert.Equal(t, big.NewInt(42), res.Height)
+	})
+
+	t.Run("websocket rejected", func(t *testing.T) {
+		res := waitWebsocket(t, func(srv *wstest.Server, stx *SignedTransaction, address string) error {
+			_, err := srv.PublishStatus(address, stx.Hash.String(), "Failure_Core_Insufficient_Balance")
+			return err
+		})
+
+		assert.Equal(t, TransactionRejected, res.State)
+		assert.Equal(t, "Failure_Core_Insufficient_Balance", res.Status)
+	})
+
+	t.Run("nil transaction", func(t *testing.T) {
+		_, err := mockServer.getTestNetClientUnsafe().Transaction.AnnounceAndWait(ctx, nil, nil)
+
+		assert.Equal(t, ErrNilSignedTransaction, err)
+	})
+}
+
+func TestSignedPayloadHeader(t *testing.T) {
+	a, err := NewAccountFromPrivateKey("787225aaff3d2c71f4ffa32d4f19ec4922f3cd869747f267378f81f8e3fcb12d", MijinTest)
+	assert.Nilf(t, err, "NewAccountFromPrivateKey returned error: %s", err)
+
+	tx, err := NewSecretProofTransaction(fakeDeadline, SHA3_512, "b778a39a3663719dfc5e48c9d78431b1e45c2af9df538782bf199c189dabeac7680ada57dcec8eee91c4e3bf3bfa9af6ffde90cd1d249d1c6121d7b759a001b1", "9a493664", MijinTest)
+	assert.Nilf(t, err, "NewSecretProofTransaction returned error: %s", err)
+
+	stx, err := a.Sign(tx)
+	assert.Nilf(t, err, "Account.Sign returned error: %s", err)
+
+	signer, err := signerFromPayload(stx.Payload)
+	assert.Nilf(t, err, "signerFromPayload returned error: %s", err)
+	assert.Equal(t, strings.ToUpper(a.PublicAccount.PublicKey), signer)
+
+	deadline, err := deadlineFromPayload(stx.Payload)
+	assert.Nilf(t, err, "deadlineFromPayload returned error: %s", err)
+	assert.Equal(t, fakeDeadline.GetInstant(), deadline.GetInstant())
+
+	_, err = signerFromPayload("00")
+	assert.Equal(t, ErrInvalidSignedPayload, err)
+}
@@ -0,0 +1,232 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/proximax-storage/nem2-crypto-go"
+	"golang.org/x/net/context"
+	"math"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	base32Alphabet                = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	defaultVanityProgressInterval = time.Second
+)
+
+// VanityOptions describes the address searched for by GenerateVanityAccount.
+// At least one of Prefix, Suffix or Regexp must be set; an address has to
+// match all of the ones which are set. Prefix and Suffix are compared with
+// the raw address, case insensitively. Every raw address of a network starts
+// with the same letter (S for MijinTest, M for Mijin, T for TestNet and N for
+// MainNet) and its second letter is one of four, so a Prefix has to start
+// with them.
+type VanityOptions struct {
+	NetworkType NetworkType
+	Prefix      string
+	Suffix      string
+	Regexp      *regexp.Regexp
+	// Workers is the number of parallel searches, runtime.NumCPU() by default.
+	Workers int
+	// Progress, when set, is called every ProgressInterval (one second by
+	// default) from a separate goroutine.
+	Progress         func(*VanityProgress)
+	ProgressInterval time.Duration
+}
+
+// VanityProgress reports how a vanity address search is going.
+type VanityProgress struct {
+	Attempts uint64
+	Elapsed  time.Duration
+	// Rate is the number of addresses tried per second.
+	Rate float64
+	// Difficulty is the expected number of attempts, zero if unknown.
+	Difficulty float64
+	// Probability is the chance to have found a match by now, zero if the
+	// difficulty is unknown.
+	Probability float64
+}
+
+// VanityDifficulty returns the expected number of attempts to find an
+// address matching the Prefix and Suffix of opts, ignoring Regexp.
+// It returns ErrInvalidVanityPattern if no address of the network can match.
+func VanityDifficulty(opts *VanityOptions) (float64, error) {
+	if opts == nil {
+		return 0, ErrNilVanityOptions
+	}
+
+	prefix, suffix := strings.ToUpper(opts.Prefix), strings.ToUpper(opts.Suffix)
+	if len(prefix) > addressRawLength || len(suffix) > addressRawLength || !isBase32(prefix) || !isBase32(suffix) {
+		return 0, ErrInvalidVanityPattern
+	}
+
+	difficulty := 1.0
+
+	for i, c := range prefix {
+		switch i {
+		case 0:
+			// the first character is given by the network byte alone
+			if byte(c) != base32Alphabet[uint8(opts.NetworkType)>>3] {
+				return 0, ErrInvalidVanityPattern
+			}
+		case 1:
+			// the second one by the 3 low bits of the network byte and 2 bits of the hash
+			first := (uint8(opts.NetworkType) & 7) << 2
+			if !strings.ContainsRune(base32Alphabet[first:first+4], c) {
+				return 0, ErrInvalidVanityPattern
+			}
+			difficulty *= 4
+		default:
+			difficulty *= 32
+		}
+	}
+
+	return difficulty * math.Pow(32, float64(len(suffix))), nil
+}
+
+// GenerateVanityAccount searches for an account whose address matches opts,
+// using opts.Workers goroutines. It returns ctx.Err() if ctx is done first.
+func GenerateVanityAccount(ctx context.Context, opts *VanityOptions) (*Account, error) {
+	difficulty, err := VanityDifficulty(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Prefix == "" && opts.Suffix == "" && opts.Regexp == nil {
+		return nil, ErrEmptyVanityPattern
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		wg       sync.WaitGroup
+		found    = make(chan *Account, 1)
+		errs     = make(chan error, 1)
+		start    = time.Now()
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			acc, err := searchVanity(ctx, opts, &attempts)
+			switch {
+			case acc != nil:
+				select {
+				case found <- acc:
+				default:
+				}
+				cancel()
+			case err != nil && ctx.Err() == nil:
+				select {
+				case errs <- err:
+				default:
+				}
+				cancel()
+			}
+		}()
+	}
+
+	if opts.Progress != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reportVanityProgress(ctx, opts, &attempts, difficulty, start)
+		}()
+	}
+
+	wg.Wait()
+
+	select {
+	case acc := <-found:
+		return acc, nil
+	case err := <-errs:
+		return nil, err
+	default:
+		return nil, ctx.Err()
+	}
+}
+
+func searchVanity(ctx context.Context, opts *VanityOptions, attempts *uint64) (*Account, error) {
+	prefix, suffix := strings.ToUpper(opts.Prefix), strings.ToUpper(opts.Suffix)
+
+	for ctx.Err() == nil {
+		kp, err := crypto.NewKeyPairByEngine(crypto.CryptoEngines.DefaultEngine)
+		if err != nil {
+			return nil, err
+		}
+
+		pKey := kp.PublicKey.String()
+		raw, err := generateEncodedAddress(pKey, opts.NetworkType)
+		if err != nil {
+			return nil, err
+		}
+		atomic.AddUint64(attempts, 1)
+
+		if !strings.HasPrefix(raw, prefix) || !strings.HasSuffix(raw, suffix) {
+			continue
+		}
+		if opts.Regexp != nil && !opts.Regexp.MatchString(raw) {
+			continue
+		}
+
+		return &Account{&PublicAccount{NewAddress(raw, opts.NetworkType), pKey}, kp}, nil
+	}
+
+	return nil, ctx.Err()
+}
+
+func reportVanityProgress(ctx context.Context, opts *VanityOptions, attempts *uint64, difficulty float64, start time.Time) {
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = defaultVanityProgressInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p := &VanityProgress{
+				Attempts: atomic.LoadUint64(attempts),
+				Elapsed:  time.Since(start),
+			}
+			p.Rate = float64(p.Attempts) / p.Elapsed.Seconds()
+
+			// the difficulty of a Regexp is unknown
+			if opts.Regexp == nil {
+				p.Difficulty = difficulty
+				p.Probability = 1 - math.Pow(1-1/difficulty, float64(p.Attempts))
+			}
+
+			opts.Progress(p)
+		}
+	}
+}
+
+func isBase32(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(base32Alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
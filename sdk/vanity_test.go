@@ -0,0 +1,87 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVanityDifficulty(t *testing.T) {
+	for _, tc := range []struct {
+		opts       VanityOptions
+		difficulty float64
+		err        error
+	}{
+		{VanityOptions{NetworkType: MijinTest}, 1, nil},
+		{VanityOptions{NetworkType: MijinTest, Prefix: "S"}, 1, nil},
+		{VanityOptions{NetworkType: MijinTest, Prefix: "sb"}, 4, nil},
+		{VanityOptions{NetworkType: MijinTest, Prefix: "SDAB"}, 4 * 32 * 32, nil},
+		{VanityOptions{NetworkType: MijinTest, Suffix: "XEM"}, 32 * 32 * 32, nil},
+		{VanityOptions{NetworkType: MainNet, Prefix: "NC", Suffix: "2"}, 4 * 32, nil},
+		{VanityOptions{NetworkType: MijinTest, Prefix: "T"}, 0, ErrInvalidVanityPattern},
+		{VanityOptions{NetworkType: MijinTest, Prefix: "SE"}, 0, ErrInvalidVanityPattern},
+		{VanityOptions{NetworkType: MijinTest, Suffix: "01"}, 0, ErrInvalidVanityPattern},
+	} {
+		d, err := VanityDifficulty(&tc.opts)
+		assert.Equal(t, tc.err, err, tc.opts)
+		assert.Equal(t, tc.difficulty, d, tc.opts)
+	}
+
+	_, err := VanityDifficulty(nil)
+	assert.Equal(t, ErrNilVanityOptions, err)
+}
+
+func TestGenerateVanityAccount(t *testing.T) {
+	acc, err := GenerateVanityAccount(ctx, &VanityOptions{
+		NetworkType: MijinTest,
+		Prefix:      "sc",
+		Suffix:      "a",
+		Workers:     2,
+	})
+	assert.Nilf(t, err, "GenerateVanityAccount returned error: %s", err)
+	assert.True(t, strings.HasPrefix(acc.Address.Address, "SC"))
+	assert.True(t, strings.HasSuffix(acc.Address.Address, "A"))
+	assert.Nil(t, ValidateAddress(acc.Address.Address))
+
+	pa, err := NewAccountFromPublicKey(acc.KeyPair.PublicKey.String(), MijinTest)
+	assert.Nil(t, err)
+	assert.True(t, pa.Address.Equals(acc.Address))
+
+	acc, err = GenerateVanityAccount(ctx, &VanityOptions{
+		NetworkType: TestNet,
+		Regexp:      regexp.MustCompile("[2-7]{3}$"),
+	})
+	assert.Nilf(t, err, "GenerateVanityAccount returned error: %s", err)
+	assert.Regexp(t, "^T.*[2-7]{3}$", acc.Address.Address)
+}
+
+func TestGenerateVanityAccount_Cancel(t *testing.T) {
+	c, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	var reports int32
+
+	_, err := GenerateVanityAccount(c, &VanityOptions{
+		NetworkType:      MijinTest,
+		Prefix:           "SAAAAAAAAAAA",
+		ProgressInterval: 10 * time.Millisecond,
+		Progress: func(p *VanityProgress) {
+			atomic.AddInt32(&reports, 1)
+			assert.Equal(t, 4*32*32*32*32*32*32*32*32*32*32.0, p.Difficulty)
+			assert.True(t, p.Probability < 0.01)
+		},
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, atomic.LoadInt32(&reports) > 0)
+
+	_, err = GenerateVanityAccount(ctx, &VanityOptions{NetworkType: MijinTest})
+	assert.Equal(t, ErrEmptyVanityPattern, err)
+}
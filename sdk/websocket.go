@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +26,10 @@ var (
 	confirmedAddedChannels     = make(map[string]chan Transaction)
 	connectsWs                 = make(map[string]*uidConn)
 	errChannels                = make(map[string]chan *ErrorInfo)
+
+	// wsMu guards the maps above, Block and the connection and uid of the
+	// clients, which are shared by the subscriptions and their readers
+	wsMu sync.Mutex
 )
 
 type uidConn struct {
@@ -33,8 +38,9 @@ type uidConn struct {
 }
 
 type sendJson struct {
-	Uid       string `json:"uid"`
-	Subscribe string `json:"subscribe"`
+	Uid         string `json:"uid"`
+	Subscribe   string `json:"subscribe,omitempty"`
+	Unsubscribe string `json:"unsubscribe,omitempty"`
 }
 
 type subscribeInfo struct {
@@ -49,6 +55,7 @@ type subscribe struct {
 	Uid       string `json:"uid"`
 	Subscribe string `json:"subscribe"`
 	conn      *websocket.Conn
+	log       Logger
 	Ch        interface{}
 }
 
@@ -58,6 +65,7 @@ type ClientWebsocket struct {
 	Uid       string
 	duration  *time.Duration
 	config    *Config
+	log       Logger
 	common    serviceWs // Reuse a single struct instead of allocating one for each service on the heap.
 	Subscribe *SubscribeService
 }
@@ -149,7 +157,10 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
-		Block.Ch <- data
+		wsMu.Lock()
+		ch := Block.Ch
+		wsMu.Unlock()
+		ch <- data
 		return nil
 
 	case "status":
@@ -158,7 +169,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := statusInfoChannels[s.account]
+		wsMu.Unlock()
 		ch <- &data
 		return nil
 
@@ -168,7 +181,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := signerInfoChannels[s.account]
+		wsMu.Unlock()
 		ch <- &data
 		return nil
 
@@ -178,7 +193,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := unconfirmedRemovedChannels[s.account]
+		wsMu.Unlock()
 		ch <- &data
 		return nil
 
@@ -188,7 +205,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := partialRemovedInfoChannels[s.account]
+		wsMu.Unlock()
 		ch <- &data
 		return nil
 
@@ -197,7 +216,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := partialAddedChannels[s.account]
+		wsMu.Unlock()
 		ch <- data
 		return nil
 
@@ -206,7 +227,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := unconfirmedAddedChannels[s.account]
+		wsMu.Unlock()
 		ch <- data
 		return nil
 
@@ -215,7 +238,9 @@ func (s *subscribeInfo) buildType(t []byte) error {
 		if err != nil {
 			return err
 		}
+		wsMu.Lock()
 		ch := confirmedAddedChannels[s.account]
+		wsMu.Unlock()
 		ch <- data
 		return nil
 	}
@@ -234,23 +259,15 @@ func (s *subscribe) getSubscribe() string {
 	return strings.Split(s.Subscribe, "/")[0]
 }
 
-func (c *ClientWebsocket) changeURLPort() {
-	c.config.BaseURL.Scheme = "ws"
-	c.config.BaseURL.Path = "/ws"
-	split := strings.Split(c.config.BaseURL.Host, ":")
-	host, port := split[0], "3000"
-	c.config.BaseURL.Host = strings.Join([]string{host, port}, ":")
-}
-
 func (c *ClientWebsocket) buildSubscribe(destination string) *subscribe {
 	b := new(subscribe)
 	b.Uid = c.Uid
 	b.Subscribe = destination
+	b.log = c.log
 	return b
 }
 
 func (c *ClientWebsocket) wsConnect() error {
-	c.changeURLPort()
 	var timeout <-chan time.Time
 	if *c.duration != time.Duration(0) {
 		timeout = time.After(*c.duration * time.Millisecond)
@@ -261,10 +278,10 @@ func (c *ClientWebsocket) wsConnect() error {
 	for {
 		select {
 		case <-timeout:
-			return fmt.Errorf("timed out")
+			return ErrWebsocketTimeout
 
 		case <-tick:
-			conn, err := websocket.Dial(c.config.BaseURL.String(), "", "http://localhost")
+			conn, err := dialWebsocket(c.config)
 
 			if err != nil {
 				return err
@@ -286,27 +303,39 @@ func (c *ClientWebsocket) wsConnect() error {
 	}
 }
 
-func (c *ClientWebsocket) reconnectWs(s *subscribe) error {
-	fmt.Println("Reconnecting Websocket....")
+// reconnectWs connects the client again and renews the subscription s. It
+// returns the new connection.
+func (c *ClientWebsocket) reconnectWs(s *subscribe) (*websocket.Conn, error) {
+	c.log.Warn("websocket reconnecting", "subscribe", s.Subscribe, "uid", s.Uid)
+
+	wsMu.Lock()
+	defer wsMu.Unlock()
 
 	if err := c.wsConnect(); err != nil {
-		return err
+		c.log.Error("websocket reconnect failed", "subscribe", s.Subscribe, "err", err)
+		return nil, err
 	}
 
 	s.Uid = c.Uid
+	connectsWs[s.getAdd()] = &uidConn{
+		uid:  c.Uid,
+		conn: c.client,
+	}
 
 	if err := websocket.JSON.Send(c.client, sendJson{
 		Uid:       s.Uid,
 		Subscribe: s.Subscribe,
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Println("New Websocket negotiated uid:", s.Uid)
+	c.log.Info("websocket reconnected", "subscribe", s.Subscribe, "uid", s.Uid)
 
-	return nil
+	return c.client, nil
 }
 
+// subsChannel sends the subscription s and starts reading its messages. It
+// is called with wsMu held.
 func (c *ClientWebsocket) subsChannel(s *subscribe) error {
 	if err := websocket.JSON.Send(c.client, sendJson{
 		Uid:       s.Uid,
@@ -314,20 +343,24 @@ func (c *ClientWebsocket) subsChannel(s *subscribe) error {
 	}); err != nil {
 		return err
 	}
+	c.log.Info("websocket subscribed", "subscribe", s.Subscribe, "uid", s.Uid)
 
 	go func() {
 		var resp []byte
 
+		wsMu.Lock()
 		address := "block"
 		if s.Subscribe != "block" {
 			address = s.getAdd()
 			c.client = connectsWs[address].conn
 		}
+		conn := c.client
 		errCh := errChannels[address]
+		wsMu.Unlock()
 
 		for {
-			if err := websocket.Message.Receive(c.client, &resp); err == io.EOF {
-				err = c.reconnectWs(s)
+			if err := websocket.Message.Receive(conn, &resp); err == io.EOF {
+				conn, err = c.reconnectWs(s)
 				if err != nil {
 					errCh <- &ErrorInfo{
 						Error: err,
@@ -336,7 +369,7 @@ func (c *ClientWebsocket) subsChannel(s *subscribe) error {
 				}
 
 			} else if err != nil {
-				err = c.reconnectWs(s)
+				conn, err = c.reconnectWs(s)
 				if err != nil {
 					errCh <- &ErrorInfo{
 						Error: err,
@@ -347,6 +380,7 @@ func (c *ClientWebsocket) subsChannel(s *subscribe) error {
 			} else {
 				subName, err := restParser(resp)
 				if err != nil {
+					c.log.Error("websocket message decode failed", "subscribe", s.Subscribe, "err", err)
 					errCh <- &ErrorInfo{
 						Error: err,
 					}
@@ -358,42 +392,62 @@ func (c *ClientWebsocket) subsChannel(s *subscribe) error {
 					account: s.getAdd(),
 				}
 
-				go func() {
+				go func(resp []byte) {
 					if err := b.buildType(resp); err != nil {
+						c.log.Error("websocket message decode failed", "subscribe", s.Subscribe, "channel", b.name, "err", err)
 						errCh <- &ErrorInfo{
 							Error: err,
 						}
 					}
-				}()
+				}(resp)
 			}
 
 			if *c.duration != time.Duration(0) {
 				tout := time.Now().Add(*c.duration * time.Millisecond)
-				c.client.SetDeadline(tout)
+				conn.SetDeadline(tout)
 			}
 		}
 	}()
 	return nil
 }
 
+// NewConnectWs connects to the websocket of the node at host.
+// host may be the REST url of the node (http://host:3000), in which case the
+// websocket is expected at ws://host:3000/ws, or a ws:// or wss:// url
+// pointing directly at the websocket endpoint.
 func NewConnectWs(host string, timeout time.Duration) (*ClientWebsocket, error) {
 	u, err := url.Parse(host)
 	if err != nil {
 		return nil, err
 	}
-	newconf := &Config{BaseURL: u}
-	c := &ClientWebsocket{config: newconf}
+	return NewConnectWsWithConfig(&Config{BaseURL: u}, timeout)
+}
+
+// NewConnectWsWithConfig connects to the websocket of the node configured by
+// conf. The websocket url is conf.WebsocketURL(); conf.Websocket sets the
+// origin, extra headers, TLS config and proxy of the connection.
+func NewConnectWsWithConfig(conf *Config, timeout time.Duration) (*ClientWebsocket, error) {
+	if _, err := conf.WebsocketURL(); err != nil {
+		return nil, err
+	}
+
+	c := &ClientWebsocket{config: conf, log: loggerOrNop(conf.Logger)}
 	c.common.client = c
 	c.Subscribe = (*SubscribeService)(&c.common)
 	c.duration = &timeout
 
-	err = c.wsConnect()
-	if err != nil {
+	if err := c.wsConnect(); err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+// SetLogger sets the Logger which receives connection and subscription
+// events. A nil Logger discards them.
+func (c *ClientWebsocket) SetLogger(l Logger) {
+	c.log = loggerOrNop(l)
+}
+
 func (s *SubscribeBlock) Unsubscribe() error {
 	return s.subscribe.unsubscribe()
 }
@@ -0,0 +1,160 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// dialWebsocket opens the websocket connection described by conf, tunnelling
+// through the configured proxy if there is one.
+func dialWebsocket(conf *Config) (*websocket.Conn, error) {
+	location, err := conf.WebsocketURL()
+	if err != nil {
+		return nil, err
+	}
+
+	wsConf, err := newWebsocketConfig(location, conf.Websocket)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := websocketProxy(location, conf.Websocket)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy == nil {
+		return websocket.DialConfig(wsConf)
+	}
+
+	conn, err := dialProxy(proxy, hostPort(location))
+	if err != nil {
+		return nil, err
+	}
+
+	if location.Scheme == "wss" {
+		tlsConf := &tls.Config{}
+		if wsConf.TlsConfig != nil {
+			tlsConf = wsConf.TlsConfig.Clone()
+		}
+		if tlsConf.ServerName == "" {
+			tlsConf.ServerName = location.Hostname()
+		}
+
+		tlsConn := tls.Client(conn, tlsConf)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	ws, err := websocket.NewClient(wsConf, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+func newWebsocketConfig(location *url.URL, conf *WebsocketConfig) (*websocket.Config, error) {
+	origin := defaultWebsocketOrigin
+	if conf != nil && conf.Origin != "" {
+		origin = conf.Origin
+	}
+
+	wsConf, err := websocket.NewConfig(location.String(), origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf != nil {
+		wsConf.TlsConfig = conf.TLSConfig
+		for k, v := range conf.Header {
+			wsConf.Header[k] = append([]string(nil), v...)
+		}
+	}
+	return wsConf, nil
+}
+
+// websocketProxy returns the proxy to use for location, or nil for a direct
+// connection.
+func websocketProxy(location *url.URL, conf *WebsocketConfig) (*url.URL, error) {
+	if conf == nil || conf.Proxy == nil {
+		return nil, nil
+	}
+
+	u := *location
+	if u.Scheme == "wss" {
+		u.Scheme = "https"
+	} else {
+		u.Scheme = "http"
+	}
+
+	return conf.Proxy(&http.Request{URL: &u, Header: make(http.Header)})
+}
+
+// dialProxy connects to the HTTP proxy and opens a CONNECT tunnel to addr.
+func dialProxy(proxy *url.URL, addr string) (net.Conn, error) {
+	conn, err := net.Dial("tcp", hostPort(proxy))
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// The tunnel starts right after the response, and nothing is sent before
+	// our handshake, so the buffered reader cannot swallow tunnelled bytes.
+	// The body of a CONNECT response is the tunnel itself, so it is not read.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT %s: %s", addr, resp.Status)
+	}
+	return conn, nil
+}
+
+// hostPort returns the host:port of u, filling in the default port of its
+// scheme.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	switch u.Scheme {
+	case "wss", "https":
+		return net.JoinHostPort(u.Hostname(), "443")
+	default:
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+}
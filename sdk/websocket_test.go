@@ -0,0 +1,332 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"github.com/proximax-storage/nem2-sdk-go/sdk/wstest"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const wsWait = 5 * time.Second
+
+var wsTestAddress = NewAddress("SBILTA367K2LX2FEXG5TFWAS7GEFYAGY7QLFBYKC", MijinTest)
+
+// resetWsConnections forgets the connections of the previous tests, as the
+// subscription bookkeeping is package-global.
+func resetWsConnections() {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
+	connectsWs = make(map[string]*uidConn)
+}
+
+// newWsTestClient starts a test server and connects a fresh client to it.
+func newWsTestClient(t *testing.T) (*wstest.Server, *ClientWebsocket) {
+	resetWsConnections()
+
+	srv := wstest.NewServer()
+	ws, err := NewConnectWs(srv.URL, 0)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("NewConnectWs returned error: %s", err)
+	}
+	return srv, ws
+}
+
+func TestNewConnectWs_URL(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	u, err := ws.config.WebsocketURL()
+	assert.Nil(t, err)
+	assert.Equal(t, srv.URL, u.String())
+	assert.NotEmpty(t, ws.Uid)
+	assert.Equal(t, 1, srv.Connections())
+	assert.Equal(t, "http://localhost", srv.Requests()[0].Header.Get("Origin"))
+}
+
+func TestConfig_WebsocketURL(t *testing.T) {
+	for in, out := range map[string]string{
+		"http://10.32.150.136:3000":          "ws://10.32.150.136:3000/ws",
+		"http://10.32.150.136:8080":          "ws://10.32.150.136:8080/ws",
+		"http://catapult.example.com":        "ws://catapult.example.com:3000/ws",
+		"https://catapult.example.com":       "wss://catapult.example.com/ws",
+		"https://gw.example.com:8443/node1/": "wss://gw.example.com:8443/node1/ws",
+		"http://[::1]":                       "ws://[::1]:3000/ws",
+		"ws://127.0.0.1:8080/custom":         "ws://127.0.0.1:8080/custom",
+		"wss://catapult.example.com/":        "wss://catapult.example.com/",
+	} {
+		cfg, err := NewConfig(in, MijinTest)
+		assert.Nil(t, err)
+
+		u, err := cfg.WebsocketURL()
+		assert.Nilf(t, err, "Config.WebsocketURL returned error for %s: %s", in, err)
+		assert.Equal(t, out, u.String())
+	}
+
+	cfg, err := NewConfig("https://catapult.example.com", MijinTest)
+	assert.Nil(t, err)
+	cfg.Websocket = &WebsocketConfig{URL: &url.URL{Scheme: "wss", Host: "ws.example.com", Path: "/events"}}
+
+	u, err := cfg.WebsocketURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "wss://ws.example.com/events", u.String())
+
+	cfg, err = NewConfig("ftp://catapult.example.com", MijinTest)
+	assert.Nil(t, err)
+
+	_, err = cfg.WebsocketURL()
+	assert.Equal(t, ErrInvalidWebsocketURL, err)
+}
+
+func TestNewConnectWsWithConfig_OriginAndHeaders(t *testing.T) {
+	resetWsConnections()
+
+	srv := wstest.NewServer()
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	assert.Nil(t, err)
+
+	ws, err := NewConnectWsWithConfig(&Config{
+		BaseURL: &url.URL{Scheme: "http", Host: u.Host},
+		Websocket: &WebsocketConfig{
+			Origin: "https://wallet.example.com",
+			Header: http.Header{"Authorization": {"Bearer token"}},
+		},
+	}, 0)
+	assert.Nilf(t, err, "NewConnectWsWithConfig returned error: %s", err)
+	assert.NotEmpty(t, ws.Uid)
+
+	req := srv.Requests()[0]
+	assert.Equal(t, "/ws", req.URL.Path)
+	assert.Equal(t, "https://wallet.example.com", req.Header.Get("Origin"))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+}
+
+func TestNewConnectWsWithConfig_TLS(t *testing.T) {
+	resetWsConnections()
+
+	srv := wstest.NewTLSServer()
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "wss", u.Scheme)
+
+	_, err = NewConnectWsWithConfig(&Config{BaseURL: &url.URL{Scheme: "https", Host: u.Host}}, 0)
+	assert.NotNil(t, err, "the test certificate should not be trusted by default")
+
+	ws, err := NewConnectWsWithConfig(&Config{
+		BaseURL:   &url.URL{Scheme: "https", Host: u.Host},
+		Websocket: &WebsocketConfig{TLSConfig: srv.ClientTLSConfig()},
+	}, 0)
+	assert.Nilf(t, err, "NewConnectWsWithConfig returned error: %s", err)
+	assert.NotEmpty(t, ws.Uid)
+}
+
+func TestNewConnectWsWithConfig_Proxy(t *testing.T) {
+	for _, secure := range []bool{false, true} {
+		resetWsConnections()
+
+		var srv *wstest.Server
+		if secure {
+			srv = wstest.NewTLSServer()
+		} else {
+			srv = wstest.NewServer()
+		}
+
+		proxy, tunnels := newConnectProxy(t)
+
+		u, err := url.Parse(srv.URL)
+		assert.Nil(t, err)
+
+		conf := &WebsocketConfig{
+			URL:   u,
+			Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: proxy.Listener.Addr().String(), User: url.UserPassword("user", "secret")}),
+		}
+		if secure {
+			conf.TLSConfig = srv.ClientTLSConfig()
+		}
+
+		ws, err := NewConnectWsWithConfig(&Config{Websocket: conf}, 0)
+		assert.Nilf(t, err, "NewConnectWsWithConfig returned error: %s", err)
+		if err == nil {
+			assert.NotEmpty(t, ws.Uid)
+		}
+
+		select {
+		case tunnel := <-tunnels:
+			assert.Equal(t, srv.Addr(), tunnel.Host)
+			assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", tunnel.Header.Get("Proxy-Authorization"))
+		default:
+			t.Error("connection did not go through the proxy")
+		}
+
+		srv.Close()
+		proxy.Close()
+	}
+}
+
+// newConnectProxy starts an HTTP proxy that only supports CONNECT tunnels.
+// Every tunnel request is sent on the returned channel.
+func newConnectProxy(t *testing.T) (*httptest.Server, <-chan *http.Request) {
+	tunnels := make(chan *http.Request, 8)
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		tunnels <- r
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			t.Error(err)
+			return
+		}
+
+		go func() {
+			io.Copy(upstream, conn)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	return proxy, tunnels
+}
+
+func TestSubscribeService_Block(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	sub, err := ws.Subscribe.Block()
+	assert.Nilf(t, err, "SubscribeService.Block returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathBlock, wsWait))
+
+	n, err := srv.PublishBlock([]byte(blockInfoJSON))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	select {
+	case block := <-sub.Ch:
+		assert.Equal(t, big.NewInt(1), block.Height)
+	case <-time.After(wsWait):
+		t.Fatal("block was not delivered")
+	}
+
+	assert.Nil(t, sub.Unsubscribe())
+	assert.Nil(t, srv.WaitUnsubscribed(pathBlock, wsWait))
+}
+
+func TestSubscribeService_Status(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	sub, err := ws.Subscribe.Status(wsTestAddress)
+	assert.Nilf(t, err, "SubscribeService.Status returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathStatus+"/"+wsTestAddress.Address, wsWait))
+
+	_, err = srv.PublishStatus(wsTestAddress.Address, "7D354E056A10E7ADAC66741D1021B0E79A57998EAD7E17198821141CE87CF63F", "Failure_Core_Insufficient_Balance")
+	assert.Nil(t, err)
+
+	select {
+	case status := <-sub.Ch:
+		assert.Equal(t, &StatusInfo{
+			Status: "Failure_Core_Insufficient_Balance",
+			Hash:   "7D354E056A10E7ADAC66741D1021B0E79A57998EAD7E17198821141CE87CF63F",
+		}, status)
+	case <-time.After(wsWait):
+		t.Fatal("status was not delivered")
+	}
+}
+
+func TestSubscribeService_Cosignature(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	sub, err := ws.Subscribe.Cosignature(wsTestAddress)
+	assert.Nilf(t, err, "SubscribeService.Cosignature returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathCosignature+"/"+wsTestAddress.Address, wsWait))
+
+	_, err = srv.PublishCosignature(wsTestAddress.Address, "A5F82EC8EBB341427B6785C8111906CD0DF18838FB11B51CE0E18B5E79DFF630", "AD3F5DB1A3E2EB2C9CB2E7E6A2C2C4E4", "671653C94E2254F2A23EFEDB15D67C38332AED1FBD24B063C0A8E675582B6A96")
+	assert.Nil(t, err)
+
+	select {
+	case signer := <-sub.Ch:
+		assert.Equal(t, "A5F82EC8EBB341427B6785C8111906CD0DF18838FB11B51CE0E18B5E79DFF630", signer.Signer)
+		assert.Equal(t, Hash("671653C94E2254F2A23EFEDB15D67C38332AED1FBD24B063C0A8E675582B6A96"), signer.ParentHash)
+	case <-time.After(wsWait):
+		t.Fatal("cosignature was not delivered")
+	}
+}
+
+func TestSubscribeService_ConfirmedAdded(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	sub, err := ws.Subscribe.ConfirmedAdded(wsTestAddress)
+	assert.Nilf(t, err, "SubscribeService.ConfirmedAdded returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathConfirmedAdded+"/"+wsTestAddress.Address, wsWait))
+
+	_, err = srv.PublishTransaction(pathConfirmedAdded, wsTestAddress.Address, []byte(transactionJson))
+	assert.Nil(t, err)
+
+	select {
+	case tx := <-sub.Ch:
+		assert.Equal(t, big.NewInt(42), tx.GetAbstractTransaction().Height)
+	case <-time.After(wsWait):
+		t.Fatal("transaction was not delivered")
+	}
+}
+
+func TestSubscribeService_Resubscribe(t *testing.T) {
+	srv, ws := newWsTestClient(t)
+	defer srv.Close()
+
+	sub, err := ws.Subscribe.Block()
+	assert.Nilf(t, err, "SubscribeService.Block returned error: %s", err)
+	assert.Nil(t, srv.WaitSubscribed(pathBlock, wsWait))
+
+	before := srv.Subscribers(pathBlock)
+	srv.DropConnections()
+
+	assert.Nil(t, srv.WaitSubscribed(pathBlock, wsWait))
+	after := srv.Subscribers(pathBlock)
+	assert.Len(t, after, 1)
+	assert.NotEqual(t, before, after)
+	wsMu.Lock()
+	assert.Equal(t, after[0], connectsWs[pathBlock].uid)
+	wsMu.Unlock()
+
+	_, err = srv.PublishBlock([]byte(blockInfoJSON))
+	assert.Nil(t, err)
+
+	select {
+	case block := <-sub.Ch:
+		assert.Equal(t, big.NewInt(1), block.Height)
+	case <-time.After(wsWait):
+		t.Fatal("block was not delivered after reconnecting")
+	}
+
+	assert.Nil(t, sub.Unsubscribe())
+	assert.Nil(t, srv.WaitUnsubscribed(pathBlock, wsWait))
+}
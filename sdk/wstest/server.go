@@ -0,0 +1,368 @@
+// Copyright 2018 ProximaX Limited. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package wstest provides an in-process Catapult WebSocket server for
+// testing subscriptions without a running node.
+//
+// The server speaks the Catapult /ws protocol: every new connection is
+// greeted with a {"uid": ...} handshake, clients send {"uid", "subscribe"}
+// and {"uid", "unsubscribe"} messages, and payloads published on a channel
+// are delivered to every live connection subscribed to it.
+package wstest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTimeout = errors.New("timed out waiting for the condition")
+
+// Message is a control message received from a client.
+type Message struct {
+	Uid         string `json:"uid"`
+	Subscribe   string `json:"subscribe,omitempty"`
+	Unsubscribe string `json:"unsubscribe,omitempty"`
+}
+
+type conn struct {
+	uid  string
+	ws   *websocket.Conn
+	subs map[string]bool
+	wmu  sync.Mutex // serializes writes to ws
+}
+
+func (c *conn) send(payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	return websocket.Message.Send(c.ws, string(payload))
+}
+
+// Server is a scriptable Catapult WebSocket server listening on a local
+// loopback address.
+type Server struct {
+	// URL is the ws:// or wss:// address of the /ws endpoint,
+	// e.g. ws://127.0.0.1:40123/ws.
+	URL string
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	changed  chan struct{} // closed and replaced whenever the state changes
+	conns    map[string]*conn
+	messages []Message
+	requests []*http.Request
+	seq      int
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer() *Server {
+	return newServer(false)
+}
+
+// NewTLSServer starts and returns a new Server serving wss://.
+// Clients should use ClientTLSConfig to trust its certificate.
+func NewTLSServer() *Server {
+	return newServer(true)
+}
+
+func newServer(secure bool) *Server {
+	s := &Server{
+		changed: make(chan struct{}),
+		conns:   make(map[string]*conn),
+	}
+	// websocket.Server without a Handshake accepts any origin.
+	handler := websocket.Server{Handler: s.serve}
+	if secure {
+		s.srv = httptest.NewTLSServer(handler)
+	} else {
+		s.srv = httptest.NewServer(handler)
+	}
+	s.URL = "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/ws"
+	return s
+}
+
+// ClientTLSConfig returns a TLS config trusting the certificate of a server
+// started by NewTLSServer.
+func (s *Server) ClientTLSConfig() *tls.Config {
+	pool := x509.NewCertPool()
+	if cert := s.srv.Certificate(); cert != nil {
+		pool.AddCert(cert)
+	}
+	return &tls.Config{RootCAs: pool}
+}
+
+// Addr returns the host:port the server listens on.
+func (s *Server) Addr() string {
+	return s.srv.Listener.Addr().String()
+}
+
+// Close drops every connection and shuts the server down.
+func (s *Server) Close() {
+	s.DropConnections()
+	s.srv.Close()
+}
+
+func (s *Server) serve(ws *websocket.Conn) {
+	s.mu.Lock()
+	s.seq++
+	c := &conn{
+		uid:  fmt.Sprintf("%016X", s.seq),
+		ws:   ws,
+		subs: make(map[string]bool),
+	}
+	s.conns[c.uid] = c
+	s.requests = append(s.requests, ws.Request())
+	s.notify()
+	s.mu.Unlock()
+
+	defer s.remove(c)
+
+	if err := c.send([]byte(fmt.Sprintf(`{"uid":"%s"}`, c.uid))); err != nil {
+		return
+	}
+
+	for {
+		var msg Message
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.messages = append(s.messages, msg)
+		if msg.Uid == c.uid {
+			if msg.Subscribe != "" {
+				c.subs[msg.Subscribe] = true
+			}
+			if msg.Unsubscribe != "" {
+				delete(c.subs, msg.Unsubscribe)
+			}
+		}
+		s.notify()
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) remove(c *conn) {
+	c.ws.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conns[c.uid] == c {
+		delete(s.conns, c.uid)
+		s.notify()
+	}
+}
+
+// notify wakes up every waiter. It must be called with s.mu held.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// DropConnections closes every live connection, as a restarting node would.
+// Clients are expected to reconnect and subscribe again.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for uid, c := range s.conns {
+		conns = append(conns, c)
+		delete(s.conns, uid)
+	}
+	s.notify()
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.ws.Close()
+	}
+}
+
+// Connections returns the number of live connections.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.conns)
+}
+
+// Messages returns every control message received so far, in arrival order.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Requests returns the handshake requests of every connection accepted so
+// far, in arrival order, e.g. to assert on the origin and headers sent.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// Subscribers returns the uids of the live connections subscribed to channel.
+func (s *Server) Subscribers(channel string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.subscribers(channel)
+}
+
+func (s *Server) subscribers(channel string) []string {
+	uids := make([]string, 0)
+	for uid, c := range s.conns {
+		if c.subs[channel] {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+// WaitSubscribed blocks until a live connection is subscribed to channel.
+// After DropConnections it can be used to assert that a client resubscribed.
+func (s *Server) WaitSubscribed(channel string, timeout time.Duration) error {
+	return s.wait(timeout, func() bool {
+		return len(s.subscribers(channel)) > 0
+	})
+}
+
+// WaitUnsubscribed blocks until no live connection is subscribed to channel.
+func (s *Server) WaitUnsubscribed(channel string, timeout time.Duration) error {
+	return s.wait(timeout, func() bool {
+		return len(s.subscribers(channel)) == 0
+	})
+}
+
+// WaitConnections blocks until exactly n connections are live.
+func (s *Server) WaitConnections(n int, timeout time.Duration) error {
+	return s.wait(timeout, func() bool {
+		return len(s.conns) == n
+	})
+}
+
+func (s *Server) wait(timeout time.Duration, cond func() bool) error {
+	deadline := time.After(timeout)
+
+	for {
+		s.mu.Lock()
+		ok, changed := cond(), s.changed
+		s.mu.Unlock()
+
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return ErrTimeout
+		}
+	}
+}
+
+// Publish sends payload to every live connection subscribed to channel and
+// returns how many connections it was delivered to.
+// payload may be a []byte or string holding raw JSON, or any value to be
+// marshalled as JSON.
+func (s *Server) Publish(channel string, payload interface{}) (int, error) {
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	default:
+		b, err := json.Marshal(p)
+		if err != nil {
+			return 0, err
+		}
+		data = b
+	}
+
+	s.mu.Lock()
+	conns := make([]*conn, 0)
+	for _, c := range s.conns {
+		if c.subs[channel] {
+			conns = append(conns, c)
+		}
+	}
+	s.mu.Unlock()
+
+	n := 0
+	for _, c := range conns {
+		if err := c.send(data); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// PublishBlock sends a block payload ({"block": ..., "meta": ...} as
+// returned by /block/{height}) to the block subscribers.
+func (s *Server) PublishBlock(block []byte) (int, error) {
+	return s.Publish("block", block)
+}
+
+// PublishTransaction sends a transaction payload ({"transaction": ...,
+// "meta": ...}) to the subscribers of channel/address, where channel is one
+// of confirmedAdded, unconfirmedAdded or partialAdded. The meta.channelName
+// field is set to channel, as Catapult does.
+func (s *Server) PublishTransaction(channel, address string, tx []byte) (int, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(tx, &obj); err != nil {
+		return 0, err
+	}
+
+	meta, _ := obj["meta"].(map[string]interface{})
+	if meta == nil {
+		meta = make(map[string]interface{})
+	}
+	meta["channelName"] = channel
+	obj["meta"] = meta
+
+	return s.Publish(channel+"/"+address, obj)
+}
+
+// PublishHash sends a {"meta": {"hash": ...}} payload to the subscribers of
+// channel/address, where channel is unconfirmedRemoved or partialRemoved.
+func (s *Server) PublishHash(channel, address, hash string) (int, error) {
+	return s.Publish(channel+"/"+address, map[string]interface{}{
+		"meta": map[string]string{
+			"channelName": channel,
+			"hash":        hash,
+		},
+	})
+}
+
+// PublishStatus sends a transaction status error to the status subscribers
+// of address.
+func (s *Server) PublishStatus(address, hash, status string) (int, error) {
+	return s.Publish("status/"+address, map[string]string{
+		"hash":   hash,
+		"status": status,
+	})
+}
+
+// PublishCosignature sends a cosignature payload to the cosignature
+// subscribers of address.
+func (s *Server) PublishCosignature(address, signer, signature, parentHash string) (int, error) {
+	return s.Publish("cosignature/"+address, map[string]string{
+		"signer":     signer,
+		"signature":  signature,
+		"parentHash": parentHash,
+	})
+}